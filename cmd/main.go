@@ -5,57 +5,464 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
 
 	"github.com/fra98/pokedex/pkg/api"
+	"github.com/fra98/pokedex/pkg/auth"
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/catalog"
+	"github.com/fra98/pokedex/pkg/client/breaker"
+	"github.com/fra98/pokedex/pkg/client/bulkhead"
+	"github.com/fra98/pokedex/pkg/client/hedge"
 	"github.com/fra98/pokedex/pkg/client/pokeapi"
+	"github.com/fra98/pokedex/pkg/client/retry"
 	"github.com/fra98/pokedex/pkg/client/translator"
+	"github.com/fra98/pokedex/pkg/client/transport"
 	"github.com/fra98/pokedex/pkg/flags"
+	"github.com/fra98/pokedex/pkg/health"
+	"github.com/fra98/pokedex/pkg/logging"
+	"github.com/fra98/pokedex/pkg/metrics"
+	"github.com/fra98/pokedex/pkg/ratelimit"
+	"github.com/fra98/pokedex/pkg/search"
 	"github.com/fra98/pokedex/pkg/server"
+	"github.com/fra98/pokedex/pkg/server/middleware"
 	"github.com/fra98/pokedex/pkg/service"
+	"github.com/fra98/pokedex/pkg/suggest"
+	"github.com/fra98/pokedex/pkg/tracing"
+	"github.com/fra98/pokedex/pkg/warmup"
+)
+
+const (
+	// jwksTimeout is the timeout of the requests fetching the JWKS from the identity provider.
+	jwksTimeout = 10 * time.Second
+	// suggestionsRetryInterval is the minimum interval between two attempts to retrieve the species list suggesting the names.
+	suggestionsRetryInterval = time.Minute
 )
 
 func main() {
 	// Initialize options for the application
 	opts := flags.Init()
 
-	var pokeClient pokeapi.Client = pokeapi.NewPokeAPIClient(nil)
-	var translationClient translator.Client = translator.NewFunTranslationClient(nil)
+	// Initialize the structured logging
+	setupLogging(opts)
 
-	if !opts.DisableCache {
-		// Initialize clients with cache
-		pokeClient = pokeapi.NewCachedPokeAPIClient(pokeClient, opts.CacheTimeoutExpiration, opts.CacheCleanupInterval)
-		translationClient = translator.NewCachedTranslationClient(translationClient, opts.CacheTimeoutExpiration, opts.CacheCleanupInterval)
+	// Initialize the tracing
+	shutdownTracing := setupTracing(opts)
+
+	// Initialize the metrics, if enabled
+	var appMetrics *metrics.Metrics
+	if !opts.DisableMetrics {
+		appMetrics = metrics.New()
+	}
+
+	// Initialize the search index, indexing the species as they are fetched from the PokeAPI
+	// (by the requests, the cache warm-up and the catalog builds)
+	var index *search.Index
+	var speciesObserver pokeapi.SpeciesObserver
+	if !opts.DisableSearch {
+		index = search.NewIndex()
+		speciesObserver = index
+	}
+
+	// Initialize the upstream clients
+	upstreams := setupClients(opts, appMetrics, speciesObserver)
+
+	switch opts.Command {
+	case flags.CommandWarm:
+		// Run the cache warm-up only, without starting the server
+		err := runWarmUp(opts, upstreams.poke)
+		flushTracing(opts, shutdownTracing)
+		if err != nil {
+			fatal("Failed to warm up cache", err)
+		}
+		return
+	case "":
+		// No command, start the server
+	default:
+		slog.Error("Unknown command", "command", opts.Command)
+		os.Exit(1)
+	}
+
+	// Initialize the readiness checks, warming up the cache in background if requested
+	healthRegistry := setupHealthChecks(opts, upstreams)
+	if opts.WarmCache {
+		startWarmUp(opts, upstreams.poke, healthRegistry)
 	}
 
 	// Initialize service
-	pokeService := service.NewPokemonService(pokeClient, translationClient)
+	var pokeService service.Pokemon = service.NewPokemonService(upstreams.poke, upstreams.translation)
+	if appMetrics != nil {
+		pokeService = service.NewInstrumentedPokemonService(pokeService, appMetrics)
+	}
 
-	// Initialize the API handler
-	pokemonHandler := api.NewPokemonHandler(pokeService)
+	// Initialize the API handlers, building the catalog in background if enabled
+	var speciesCatalog *catalog.Catalog
+	var catalogHandler *api.CatalogHandler
+	if opts.EnableCatalog {
+		speciesCatalog = startCatalog(opts, upstreams.poke, healthRegistry)
+		catalogHandler = api.NewCatalogHandler(speciesCatalog)
+	}
+	var searchHandler *api.SearchHandler
+	if index != nil {
+		searchHandler = api.NewSearchHandler(index)
+	}
+	var suggester *suggest.Suggester
+	if !opts.DisableSuggestions {
+		suggester = suggest.NewSuggester(newSuggestionSource(upstreams.poke, speciesCatalog), &suggest.Config{})
+	}
+	pokemonHandler := api.NewPokemonHandler(pokeService, suggester)
+	healthHandler := api.NewHealthHandler(healthRegistry)
+	cacheHandler := api.NewCacheAdminHandler(upstreams.caches)
 
 	// Setup the server
-	srv := setupServer(opts, pokemonHandler)
+	srv := setupServer(opts, appMetrics, pokemonHandler, catalogHandler, searchHandler, healthHandler, cacheHandler)
+
+	// Run the server, flushing the pending spans once stopped
+	err := runServer(srv, opts)
+	flushTracing(opts, shutdownTracing)
+	if err != nil {
+		fatal("Failed to run server", err)
+	}
+}
+
+// setupLogging sets up the default structured logger according to the options.
+// Gin runs in release mode unless configured otherwise, since its debug output is not structured.
+func setupLogging(opts *flags.Options) {
+	logger, err := logging.New(os.Stderr, &logging.Config{Level: opts.LogLevel, Format: opts.LogFormat})
+	if err != nil {
+		fatal("Failed to setup logging", err)
+	}
+	slog.SetDefault(logger)
+
+	if os.Getenv(gin.EnvGinMode) == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+}
+
+// fatal logs the error and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// setupTracing sets up the tracing according to the options, returning the function to flush the pending spans.
+func setupTracing(opts *flags.Options) func(context.Context) error {
+	shutdown, err := tracing.Setup(context.Background(), &tracing.Config{
+		Exporter:     opts.TracingExporter,
+		OTLPEndpoint: opts.TracingOTLPEndpoint,
+		OTLPInsecure: opts.TracingOTLPInsecure,
+		SampleRatio:  opts.TracingSampleRatio,
+	})
+	if err != nil {
+		fatal("Failed to setup tracing", err)
+	}
+	return shutdown
+}
+
+func flushTracing(opts *flags.Options, shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+}
+
+// clients groups the upstream clients, along with the caches, circuit breakers and bulkheads wrapping them.
+type clients struct {
+	poke        pokeapi.Client
+	translation translator.Client
+	caches      map[string]cache.Cache
+	breakers    []*breaker.Breaker
+	bulkheads   []*bulkhead.Bulkhead
+
+	// Probes checking the reachability of the upstreams, bypassing all the decorators
+	pokeProbe        health.Checker
+	translationProbe health.Checker
+}
+
+// setupClients returns the upstream clients wrapped by the decorators enabled by the options.
+// If metrics are provided, the upstream requests and the state of the decorators are recorded.
+// If a species observer is provided, it is notified of the species fetched from the PokeAPI, but not of the cache hits.
+func setupClients(opts *flags.Options, m *metrics.Metrics, speciesObserver pokeapi.SpeciesObserver) *clients {
+	retryPolicy := &retry.Policy{
+		MaxAttempts:          opts.RetryMaxAttempts,
+		BackoffBase:          opts.RetryBackoffBase,
+		BackoffCap:           opts.RetryBackoffCap,
+		Jitter:               opts.RetryJitter,
+		RetryableStatusCodes: opts.RetryStatusCodes,
+		RetryNetworkErrors:   opts.RetryNetworkErrors,
+	}
+
+	pokeTransport := newTransportConfig(opts, retryPolicy)
+	pokeTransport.Timeout = opts.PokeAPITimeout
+	translatorTransport := newTransportConfig(opts, retryPolicy)
+	translatorTransport.Timeout = opts.TranslatorTimeout
+
+	pokeClient := pokeapi.NewPokeAPIClient(nil, pokeTransport)
+	translationClient := translator.NewFunTranslationClient(nil, translatorTransport)
+	c := &clients{
+		poke:             pokeClient,
+		translation:      translationClient,
+		caches:           map[string]cache.Cache{},
+		pokeProbe:        health.CheckerFunc(pokeClient.Ping),
+		translationProbe: health.CheckerFunc(translationClient.Ping),
+	}
+
+	if m != nil {
+		// Record every request actually sent to the upstreams, including the hedged ones
+		c.poke = pokeapi.NewInstrumentedPokeAPIClient(c.poke, m)
+		c.translation = translator.NewInstrumentedTranslationClient(c.translation, m)
+	}
+
+	if opts.HedgeRequests {
+		// Hedge the slow PokeAPI requests, cutting the tail latency
+		hedger := hedge.New(&hedge.Config{
+			Percentile:         opts.HedgePercentile,
+			MinDelay:           opts.HedgeMinDelay,
+			MaxDelay:           opts.HedgeMaxDelay,
+			MaxHedgesPerSecond: opts.HedgeMaxPerSecond,
+		})
+		if m != nil {
+			m.RegisterHedger("pokeapi", hedger)
+		}
+		c.poke = pokeapi.NewHedgedPokeAPIClient(c.poke, hedger)
+	}
+
+	if !opts.DisableCircuitBreaker {
+		// Wrap clients with circuit breakers, failing fast while the upstreams are unavailable
+		breakerConfig := &breaker.Config{
+			FailureThreshold:    opts.BreakerFailureThreshold,
+			OpenTimeout:         opts.BreakerOpenTimeout,
+			HalfOpenMaxRequests: opts.BreakerHalfOpenRequests,
+		}
+		pokeBreaker := breaker.New("pokeapi", breakerConfig)
+		translationBreaker := breaker.New("translator", breakerConfig)
+		c.breakers = append(c.breakers, pokeBreaker, translationBreaker)
+		c.poke = pokeapi.NewCircuitBreakerPokeAPIClient(c.poke, pokeBreaker)
+		c.translation = translator.NewCircuitBreakerTranslationClient(c.translation, translationBreaker)
+	}
+
+	// Wrap clients with bulkheads, limiting the concurrent requests to each upstream.
+	// Bulkheads are outside the circuit breakers, so that rejected requests are not counted as upstream failures.
+	if opts.PokeAPIMaxConcurrency > 0 {
+		pokeBulkhead := bulkhead.New("pokeapi", &bulkhead.Config{
+			MaxConcurrent: opts.PokeAPIMaxConcurrency,
+			MaxQueue:      opts.PokeAPIMaxQueue,
+			QueueTimeout:  opts.UpstreamQueueTimeout,
+		})
+		c.bulkheads = append(c.bulkheads, pokeBulkhead)
+		c.poke = pokeapi.NewBulkheadPokeAPIClient(c.poke, pokeBulkhead)
+	}
+	if opts.TranslatorMaxConcurrency > 0 {
+		translationBulkhead := bulkhead.New("translator", &bulkhead.Config{
+			MaxConcurrent: opts.TranslatorMaxConcurrency,
+			MaxQueue:      opts.TranslatorMaxQueue,
+			QueueTimeout:  opts.UpstreamQueueTimeout,
+		})
+		c.bulkheads = append(c.bulkheads, translationBulkhead)
+		c.translation = translator.NewBulkheadTranslationClient(c.translation, translationBulkhead)
+	}
+
+	if speciesObserver != nil {
+		// Notify the observer of the species fetched from the PokeAPI, inside the cache so that the cache hits are not notified again
+		c.poke = pokeapi.NewObservedPokeAPIClient(c.poke, speciesObserver)
+	}
+
+	if !opts.DisableCache {
+		// Initialize clients with cache
+		c.caches["pokeapi"] = newCache(opts)
+		c.caches["translation"] = newCache(opts)
+		c.poke = pokeapi.NewCachedPokeAPIClient(c.poke, c.caches["pokeapi"], opts.CacheTimeoutExpiration, opts.CacheRevalidationWindow)
+		c.translation = translator.NewCachedTranslationClient(c.translation, c.caches["translation"])
+	}
+
+	if m != nil {
+		for _, b := range c.breakers {
+			m.RegisterBreaker(b)
+		}
+		for _, b := range c.bulkheads {
+			m.RegisterBulkhead(b)
+		}
+		for name, cc := range c.caches {
+			m.RegisterCache(name, cc)
+		}
+	}
+
+	return c
+}
+
+// setupHealthChecks returns the registry of the readiness checks of the upstreams and caches.
+// The PokeAPI is critical, while the translation API is not, since the original description is returned when unavailable.
+func setupHealthChecks(opts *flags.Options, upstreams *clients) *health.Registry {
+	registry := health.NewRegistry()
+
+	registry.Register("pokeapi", health.NewCachedChecker(upstreams.pokeProbe, opts.ReadinessProbeInterval, opts.ReadinessProbeTimeout), true)
+	registry.Register("translator", health.NewCachedChecker(upstreams.translationProbe, opts.ReadinessProbeInterval, opts.ReadinessProbeTimeout), false)
+	for _, b := range upstreams.breakers {
+		registry.Register(b.Name()+"-circuit-breaker", b, false)
+	}
+	for _, b := range upstreams.bulkheads {
+		registry.Register(b.Name()+"-bulkhead", b, false)
+	}
+	for name, c := range upstreams.caches {
+		registry.Register(name+"-cache", health.CheckerFunc(c.Ping), false)
+	}
+	return registry
+}
+
+func newTransportConfig(opts *flags.Options, retryPolicy *retry.Policy) *transport.Config {
+	cfg := &transport.Config{
+		MaxIdleConns:    opts.UpstreamMaxIdleConns,
+		IdleConnTimeout: opts.UpstreamIdleConnTimeout,
+		KeepAlive:       opts.UpstreamKeepAlive,
+		UserAgent:       opts.UpstreamUserAgent,
+		Retry:           retryPolicy,
+	}
+
+	if opts.UpstreamCAFile != "" {
+		rootCAs, err := transport.LoadCertPool(opts.UpstreamCAFile)
+		if err != nil {
+			fatal("Failed to load upstream CA bundle", err)
+		}
+		cfg.RootCAs = rootCAs
+	}
+
+	if opts.UpstreamProxyURL != "" {
+		proxyURL, err := url.Parse(opts.UpstreamProxyURL)
+		if err != nil {
+			fatal("Failed to parse upstream proxy URL", err)
+		}
+		cfg.ProxyURL = proxyURL
+	}
+
+	return cfg
+}
+
+func newCache(opts *flags.Options) cache.Cache { //nolint:ireturn // the backend is selected at runtime
+	c, err := cache.New(&cache.Config{
+		DefaultExpiration: opts.CacheTimeoutExpiration,
+		CleanupInterval:   opts.CacheCleanupInterval,
+		MaxEntries:        opts.CacheMaxEntries,
+		MaxBytes:          opts.CacheMaxBytes,
+		Policy:            cache.Policy(opts.CacheEvictionPolicy),
+	})
+	if err != nil {
+		fatal("Failed to initialize cache", err)
+	}
+	return c
+}
+
+func newWarmer(opts *flags.Options, pokeClient pokeapi.Client) *warmup.Warmer {
+	return warmup.NewWarmer(pokeClient, &warmup.Config{
+		Concurrency:    opts.WarmCacheConcurrency,
+		Interval:       opts.WarmCacheInterval,
+		ReadyThreshold: opts.WarmCacheReadyThreshold,
+	})
+}
+
+func startWarmUp(opts *flags.Options, pokeClient pokeapi.Client, healthRegistry *health.Registry) {
+	if opts.DisableCache {
+		slog.Warn("Cache warm-up skipped since the cache is disabled")
+		return
+	}
+
+	warmer := newWarmer(opts, pokeClient)
+	healthRegistry.Register("cache-warmup", warmer, true)
+	go warmer.RunWithRetries(context.Background())
+}
+
+func runWarmUp(opts *flags.Options, pokeClient pokeapi.Client) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := newWarmer(opts, pokeClient).Run(ctx); err != nil {
+		return fmt.Errorf("cache warm-up aborted: %w", err)
+	}
+	return nil
+}
 
-	// Run the server
-	if err := runServer(srv, opts); err != nil {
-		log.Fatalf("Failed to run server: %v", err)
+// startCatalog starts building the Pokemon catalog in background, through the given client to warm its cache too.
+// The catalog is not critical for the readiness, since only the listing endpoint depends on it.
+func startCatalog(opts *flags.Options, pokeClient pokeapi.Client, healthRegistry *health.Registry) *catalog.Catalog {
+	c := catalog.NewCatalog(pokeClient, &catalog.Config{
+		Concurrency:     opts.CatalogConcurrency,
+		Interval:        opts.CatalogInterval,
+		RefreshInterval: opts.CatalogRefreshInterval,
+		File:            opts.CatalogFile,
+	})
+	healthRegistry.Register("catalog", c, false)
+	go c.Run(context.Background())
+	return c
+}
+
+// newSuggestionSource returns the source of the species names suggested for the Pokemon not found:
+// the catalog if enabled, so that the species list is not retrieved twice, otherwise the species list retrieved from the PokeAPI.
+func newSuggestionSource(pokeClient pokeapi.Client, speciesCatalog *catalog.Catalog) suggest.Source { //nolint:ireturn // selected at runtime
+	if speciesCatalog != nil {
+		return speciesCatalog
 	}
+	return suggest.NewSpeciesList(pokeClient, &suggest.ListConfig{RetryInterval: suggestionsRetryInterval})
 }
 
-func setupServer(opts *flags.Options, pokemonHandler *api.PokemonHandler) *http.Server {
+func setupServer(opts *flags.Options, appMetrics *metrics.Metrics, pokemonHandler *api.PokemonHandler, catalogHandler *api.CatalogHandler,
+	searchHandler *api.SearchHandler, healthHandler *api.HealthHandler, cacheHandler *api.CacheAdminHandler) *http.Server {
 	// Setup the Gin engine
 	engine := server.SetupEngine()
+	if err := engine.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		fatal("Failed to set trusted proxies", err)
+	}
 
 	// Setup the middlewares
-	server.SetupMiddlewares(engine)
+	cors := middleware.CORSConfig{
+		AllowedOrigins:   opts.CORSAllowedOrigins,
+		AllowedMethods:   opts.CORSAllowedMethods,
+		AllowedHeaders:   opts.CORSAllowedHeaders,
+		AllowCredentials: opts.CORSAllowCredentials,
+		MaxAge:           opts.CORSMaxAge,
+	}
+	if err := cors.Validate(); err != nil {
+		fatal("Invalid CORS configuration", err)
+	}
+	var compress *middleware.CompressConfig
+	if !opts.DisableCompression {
+		compress = &middleware.CompressConfig{MinSize: opts.CompressionMinSize, ExcludedContentTypes: opts.CompressionExcludedTypes}
+	}
+	server.SetupMiddlewares(engine, appMetrics, cors, compress)
 
 	// Register the API endpoints
-	server.RegisterEndpoints(engine, pokemonHandler)
+	httpCache := middleware.HTTPCacheConfig{
+		MaxAge:         opts.HTTPCacheMaxAge,
+		DegradedMaxAge: opts.HTTPCacheDegradedMaxAge,
+	}
+	if httpCache.MaxAge == 0 {
+		httpCache.MaxAge = opts.CacheTimeoutExpiration
+	}
+	rateLimit := server.RateLimitConfig{
+		Store:      ratelimit.NewMemoryStore(),
+		Pokemon:    ratelimit.Limit{Rate: opts.RateLimitRate, Burst: opts.RateLimitBurst},
+		Translated: ratelimit.Limit{Rate: opts.RateLimitTranslatedRate, Burst: opts.RateLimitTranslatedBurst},
+	}
+	authCfg := setupAuth(opts)
+	server.RegisterEndpoints(engine, pokemonHandler, catalogHandler, searchHandler, httpCache, rateLimit, authCfg)
+	server.RegisterHealthEndpoints(engine, healthHandler)
+	if appMetrics != nil {
+		server.RegisterMetricsEndpoint(engine, appMetrics)
+	}
+	if authCfg.Authenticator != nil && authCfg.Authenticator.HasScope(auth.ScopeAdmin) {
+		var keysHandler *api.APIKeyAdminHandler
+		if keyring := authCfg.Authenticator.Keyring(); keyring != nil {
+			keysHandler = api.NewAPIKeyAdminHandler(keyring)
+		}
+		server.RegisterAdminEndpoints(engine, authCfg.Authenticator, cacheHandler, keysHandler)
+	}
 
 	return &http.Server{
 		Addr:         opts.Address,
@@ -65,11 +472,81 @@ func setupServer(opts *flags.Options, pokemonHandler *api.PokemonHandler) *http.
 	}
 }
 
+// setupAuth loads the API keys from the file and the environment, along with the admin token as key with the admin scope,
+// and the JWKS verifying the JWTs. Without API keys (besides the admin token) nor JWKS, the Pokemon endpoints are open to anonymous requests.
+func setupAuth(opts *flags.Options) server.AuthConfig {
+	var keys []auth.Key
+	if opts.APIKeysFile != "" {
+		fileKeys, err := auth.LoadKeys(opts.APIKeysFile)
+		if err != nil {
+			fatal("Failed to load API keys", err)
+		}
+		keys = append(keys, fileKeys...)
+	}
+	if opts.APIKeys != "" {
+		envKeys, err := auth.ParseKeys([]byte(opts.APIKeys))
+		if err != nil {
+			fatal("Failed to parse API keys", err)
+		}
+		keys = append(keys, envKeys...)
+	}
+	verifier := setupTokenVerifier(opts)
+	allowAnonymous := opts.AllowAnonymous || (len(keys) == 0 && verifier == nil)
+
+	if opts.AdminToken != "" {
+		keys = append(keys, auth.Key{Name: "admin-token", Secret: opts.AdminToken, Scopes: []auth.Scope{auth.ScopeAdmin}})
+	}
+	if len(keys) == 0 && verifier == nil {
+		return server.AuthConfig{}
+	}
+
+	var keyring *auth.Keyring
+	if len(keys) > 0 {
+		var err error
+		if keyring, err = auth.NewKeyring(keys); err != nil {
+			fatal("Invalid API keys", err)
+		}
+	}
+	return server.AuthConfig{Authenticator: auth.NewAuthenticator(keyring, verifier), AllowAnonymous: allowAnonymous}
+}
+
+// setupTokenVerifier loads the JWKS verifying the JWTs, if configured.
+func setupTokenVerifier(opts *flags.Options) *auth.TokenVerifier {
+	if opts.JWKSURL == "" && opts.JWKSFile == "" {
+		return nil
+	}
+
+	jwksTransport := newTransportConfig(opts, nil)
+	jwksTransport.Timeout = jwksTimeout
+	jwks, err := auth.NewJWKS(context.Background(), auth.JWKSConfig{
+		URL:                opts.JWKSURL,
+		File:               opts.JWKSFile,
+		RefreshInterval:    opts.JWKSRefreshInterval,
+		MinRefreshInterval: opts.JWKSMinRefreshInterval,
+		HTTPClient:         transport.NewClient(jwksTransport),
+	})
+	if err != nil {
+		fatal("Failed to load JWKS", err)
+	}
+
+	verifier, err := auth.NewTokenVerifier(auth.TokenConfig{
+		Issuer:      opts.JWTIssuer,
+		Audience:    opts.JWTAudience,
+		ScopeClaim:  opts.JWTScopeClaim,
+		ScopePrefix: opts.JWTScopePrefix,
+		Leeway:      opts.JWTLeeway,
+	}, jwks)
+	if err != nil {
+		fatal("Invalid JWT configuration", err)
+	}
+	return verifier
+}
+
 func runServer(srv *http.Server, opts *flags.Options) error {
 	// Start the server in a separate goroutine to avoid blocking the main thread and handle graceful shutdown
 	chanErrors := make(chan error)
 	go func() {
-		log.Printf("Starting server on %s...", srv.Addr)
+		slog.Info("Starting server", "address", srv.Addr)
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			chanErrors <- err
 		} else {
@@ -87,7 +564,7 @@ func runServer(srv *http.Server, opts *flags.Options) error {
 			return fmt.Errorf("failed to start server: %w", err)
 		}
 	case sign := <-chanSignals:
-		log.Printf("Shutting down server due to signal %q...", sign)
+		slog.Info("Shutting down server", "signal", sign.String())
 
 		// Wait for the server to finish processing active requests within the timeout
 		ctx, cancel := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
@@ -97,7 +574,7 @@ func runServer(srv *http.Server, opts *flags.Options) error {
 			return fmt.Errorf("failed to shutdown server: %w", err)
 		}
 
-		log.Println("Server gracefully shutdown")
+		slog.Info("Server gracefully shutdown")
 	}
 
 	return nil
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/auth"
+	"github.com/fra98/pokedex/pkg/models"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/render"
+)
+
+// APIKeyAdminHandler handles the API keys administration endpoints.
+type APIKeyAdminHandler struct {
+	keyring *auth.Keyring
+}
+
+// NewAPIKeyAdminHandler creates a new APIKeyAdminHandler for the given keyring.
+func NewAPIKeyAdminHandler(keyring *auth.Keyring) *APIKeyAdminHandler {
+	return &APIKeyAdminHandler{keyring: keyring}
+}
+
+// GetUsage returns the usage of each API key, along with its daily quota.
+func (h *APIKeyAdminHandler) GetUsage(c *gin.Context) {
+	usage := h.keyring.Usage()
+	res := models.APIKeysUsageResponse{Keys: make([]models.APIKeyUsage, 0, len(usage))}
+	for _, u := range usage {
+		scopes := make([]string, 0, len(u.Scopes))
+		for _, scope := range u.Scopes {
+			scopes = append(scopes, string(scope))
+		}
+		res.Keys = append(res.Keys, models.APIKeyUsage{
+			Name:          u.Name,
+			Scopes:        scopes,
+			Disabled:      u.Disabled,
+			DailyQuota:    u.DailyQuota,
+			UsedToday:     u.UsedToday,
+			RejectedToday: u.RejectedToday,
+			TotalRequests: u.TotalRequests,
+			ResetAt:       u.ResetAt,
+		})
+	}
+	render.Render(c, http.StatusOK, res)
+}
+
+// DisableKey revokes the API key given as path parameter, until it is enabled again or the server restarts.
+func (h *APIKeyAdminHandler) DisableKey(c *gin.Context) {
+	h.setDisabled(c, true)
+}
+
+// EnableKey re-enables the API key given as path parameter.
+func (h *APIKeyAdminHandler) EnableKey(c *gin.Context) {
+	h.setDisabled(c, false)
+}
+
+func (h *APIKeyAdminHandler) setDisabled(c *gin.Context, disabled bool) {
+	name := c.Param("name")
+	if err := h.keyring.SetDisabled(name, disabled); err != nil {
+		_ = c.Error(httperror.NewHTTPError(http.StatusNotFound, httperror.CodeAPIKeyNotFound, fmt.Sprintf("no API key named %q", name)))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
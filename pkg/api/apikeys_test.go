@@ -0,0 +1,176 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/api"
+	"github.com/fra98/pokedex/pkg/auth"
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/models"
+	"github.com/fra98/pokedex/pkg/server"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+)
+
+// staticService is a service.Pokemon returning the same Pokemon for every request.
+type staticService struct{}
+
+func (staticService) GetPokemonInfo(_ context.Context, name string) (*models.PokemonResponse, error) {
+	return &models.PokemonResponse{Name: name, Description: "description", Habitat: "rare", IsLegendary: true}, nil
+}
+
+func (staticService) GetTranslatedPokemonInfo(_ context.Context, name string) (*models.PokemonResponse, error) {
+	return &models.PokemonResponse{Name: name, Description: "translated description", Habitat: "rare", IsLegendary: true}, nil
+}
+
+const testOrigin = "https://pokedex.example.com"
+
+// setupAuth is a helper function to setup a gin engine serving the Pokemon and administration endpoints,
+// authorized by a partner key with the read scope only and a daily quota of 2 requests, and by an admin key.
+// The cross-origin requests are allowed from the test origin.
+func setupAuth(t *testing.T, allowAnonymous bool) *gin.Engine {
+	t.Helper()
+
+	keyring, err := auth.NewKeyring([]auth.Key{
+		{Name: "partner", Secret: "partner-key", Scopes: []auth.Scope{auth.ScopeRead}, DailyQuota: 2},
+		{Name: "admin", Secret: testAdminToken, Scopes: []auth.Scope{auth.ScopeAdmin}},
+	})
+	require.NoError(t, err)
+
+	engine := gin.New()
+	server.SetupMiddlewares(engine, nil, middleware.CORSConfig{AllowedOrigins: []string{testOrigin}, AllowedMethods: []string{http.MethodGet}}, nil)
+	server.RegisterEndpoints(engine, api.NewPokemonHandler(staticService{}, nil), nil, nil, middleware.HTTPCacheConfig{}, server.RateLimitConfig{},
+		server.AuthConfig{Authenticator: auth.NewAuthenticator(keyring, nil), AllowAnonymous: allowAnonymous})
+	server.RegisterAdminEndpoints(engine, auth.NewAuthenticator(keyring, nil), api.NewCacheAdminHandler(map[string]cache.Cache{}),
+		api.NewAPIKeyAdminHandler(keyring))
+	return engine
+}
+
+func doRequest(engine *gin.Engine, method, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, http.NoBody)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	t.Parallel()
+
+	engine := setupAuth(t, false)
+	partner := map[string]string{middleware.APIKeyHeader: "partner-key"}
+
+	// Requests without a valid API key are rejected
+	w := doRequest(engine, http.MethodGet, "/v1/pokemon/mewtwo", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+	w = doRequest(engine, http.MethodGet, "/v1/pokemon/mewtwo", map[string]string{"Authorization": "Bearer wrong"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// The key is accepted in both headers, on the routes requiring its scope only
+	w = doRequest(engine, http.MethodGet, "/v1/pokemon/mewtwo", partner)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doRequest(engine, http.MethodGet, "/v1/pokemon/mewtwo", map[string]string{"Authorization": "Bearer partner-key"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The authorized responses are not stored by the shared caches
+	assert.Equal(t, "private, max-age=0", w.Header().Get("Cache-Control"))
+
+	w = doRequest(engine, http.MethodGet, "/v1/pokemon/translated/mewtwo", partner)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doRequest(engine, http.MethodGet, "/admin/cache/stats", partner)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// The daily quota is exhausted
+	w = doRequest(engine, http.MethodGet, "/v1/pokemon/mewtwo", partner)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	var problem httperror.HTTPError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, httperror.CodeQuotaExceeded, problem.Code)
+}
+
+func TestAPIKeyAuth_Anonymous(t *testing.T) {
+	t.Parallel()
+
+	engine := setupAuth(t, true)
+
+	// Anonymous requests are allowed on the Pokemon endpoints, but not on the administration ones
+	w := doRequest(engine, http.MethodGet, "/v1/pokemon/translated/mewtwo", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doRequest(engine, http.MethodGet, "/admin/keys", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Invalid API keys are still rejected
+	w = doRequest(engine, http.MethodGet, "/v1/pokemon/mewtwo", map[string]string{middleware.APIKeyHeader: "wrong"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAPIKeyAdmin(t *testing.T) {
+	t.Parallel()
+
+	engine := setupAuth(t, false)
+	admin := map[string]string{"Authorization": "Bearer " + testAdminToken}
+	partner := map[string]string{middleware.APIKeyHeader: "partner-key"}
+
+	w := doRequest(engine, http.MethodGet, "/v1/pokemon/mewtwo", partner)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// The usage of the keys is reported
+	w = doRequest(engine, http.MethodGet, "/admin/keys", admin)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var usage models.APIKeysUsageResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &usage))
+	require.Len(t, usage.Keys, 2)
+	assert.Equal(t, "partner", usage.Keys[1].Name)
+	assert.Equal(t, 1, usage.Keys[1].UsedToday)
+	assert.Equal(t, 2, usage.Keys[1].DailyQuota)
+
+	// The disabled keys are cut off
+	w = doRequest(engine, http.MethodPost, "/admin/keys/partner/disable", admin)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = doRequest(engine, http.MethodGet, "/v1/pokemon/mewtwo", partner)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = doRequest(engine, http.MethodPost, "/admin/keys/partner/enable", admin)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = doRequest(engine, http.MethodGet, "/v1/pokemon/mewtwo", partner)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doRequest(engine, http.MethodPost, "/admin/keys/unknown/disable", admin)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	t.Parallel()
+
+	engine := setupAuth(t, false)
+
+	// The preflight requests bypass the authorization, on both the Pokemon and the administration endpoints
+	for _, target := range []string{"/v1/pokemon/mewtwo", "/v1/pokemon/translated/mewtwo", "/admin/keys"} {
+		w := doRequest(engine, http.MethodOptions, target, map[string]string{
+			"Origin":                         testOrigin,
+			"Access-Control-Request-Method":  http.MethodGet,
+			"Access-Control-Request-Headers": "x-api-key",
+		})
+		assert.Equal(t, http.StatusNoContent, w.Code, target)
+		assert.Equal(t, testOrigin, w.Header().Get("Access-Control-Allow-Origin"), target)
+	}
+
+	// The actual requests are authorized, and their errors are readable by the cross-origin clients
+	w := doRequest(engine, http.MethodGet, "/v1/pokemon/mewtwo", map[string]string{"Origin": testOrigin})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, testOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/models"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/render"
+)
+
+// CacheAdminHandler handles the cache administration API endpoints.
+type CacheAdminHandler struct {
+	caches map[string]cache.Cache
+	names  []string
+}
+
+// NewCacheAdminHandler creates a new CacheAdminHandler for the given caches, indexed by name.
+func NewCacheAdminHandler(caches map[string]cache.Cache) *CacheAdminHandler {
+	names := make([]string, 0, len(caches))
+	for name := range caches {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return &CacheAdminHandler{caches: caches, names: names}
+}
+
+// GetStats returns the hit, miss and eviction statistics of each cache.
+func (h *CacheAdminHandler) GetStats(c *gin.Context) {
+	res := make(models.CacheStatsResponse, len(h.caches))
+	for name, ch := range h.caches {
+		res[name] = ch.Stats()
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// ListKeys returns the keys starting with the prefix given as query parameter.
+func (h *CacheAdminHandler) ListKeys(c *gin.Context) {
+	prefix := c.Query("prefix")
+
+	res := models.CacheKeysResponse{Keys: []models.CacheKey{}}
+	for _, name := range h.names {
+		for _, key := range h.caches[name].Keys(prefix) {
+			res.Keys = append(res.Keys, models.CacheKey{Cache: name, Key: key})
+		}
+	}
+	render.Render(c, http.StatusOK, res)
+}
+
+// PurgeKeys removes the keys starting with the prefix given as query parameter.
+func (h *CacheAdminHandler) PurgeKeys(c *gin.Context) {
+	prefix := c.Query("prefix")
+	if prefix == "" {
+		_ = c.Error(httperror.NewHTTPError(http.StatusBadRequest, httperror.CodeInvalidRequest, "missing prefix query parameter"))
+		return
+	}
+
+	res := models.CachePurgeResponse{}
+	for _, ch := range h.caches {
+		res.Deleted += ch.DeletePrefix(prefix)
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetEntry returns the entry stored for the key given as query parameter, along with its age and expiry.
+func (h *CacheAdminHandler) GetEntry(c *gin.Context) {
+	key := c.Query("key")
+	if key == "" {
+		_ = c.Error(httperror.NewHTTPError(http.StatusBadRequest, httperror.CodeInvalidRequest, "missing key query parameter"))
+		return
+	}
+
+	for _, name := range h.names {
+		entry, found := h.caches[name].Peek(key)
+		if !found {
+			continue
+		}
+
+		now := time.Now()
+		res := models.CacheEntryResponse{
+			Cache:     name,
+			Key:       entry.Key,
+			Value:     entry.Value,
+			Size:      entry.Size,
+			CreatedAt: entry.CreatedAt,
+			Age:       now.Sub(entry.CreatedAt).Round(time.Second).String(),
+		}
+		if !entry.ExpiresAt.IsZero() {
+			res.ExpiresAt = &entry.ExpiresAt
+			res.TTL = entry.ExpiresAt.Sub(now).Round(time.Second).String()
+		}
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	_ = c.Error(httperror.NewHTTPError(http.StatusNotFound, httperror.CodeCacheEntryNotFound, fmt.Sprintf("no cache entry stored for key %q", key)))
+}
+
+// DeleteEntry removes the entry stored for the key given as query parameter.
+func (h *CacheAdminHandler) DeleteEntry(c *gin.Context) {
+	key := c.Query("key")
+	if key == "" {
+		_ = c.Error(httperror.NewHTTPError(http.StatusBadRequest, httperror.CodeInvalidRequest, "missing key query parameter"))
+		return
+	}
+
+	for _, ch := range h.caches {
+		if _, found := ch.Peek(key); found {
+			ch.Delete(key)
+			c.Status(http.StatusNoContent)
+			return
+		}
+	}
+
+	_ = c.Error(httperror.NewHTTPError(http.StatusNotFound, httperror.CodeCacheEntryNotFound, fmt.Sprintf("no cache entry stored for key %q", key)))
+}
@@ -0,0 +1,137 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/api"
+	"github.com/fra98/pokedex/pkg/auth"
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/models"
+	"github.com/fra98/pokedex/pkg/server"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+)
+
+const testAdminToken = "secret"
+
+// setupCacheAdmin is a helper function to setup a gin engine serving the admin endpoints on two caches,
+// a bounded one and an unbounded one, pre-populated with some entries.
+func setupCacheAdmin(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	bounded, err := cache.NewBoundedCache(&cache.Config{MaxEntries: 10, DefaultExpiration: time.Hour})
+	require.NoError(t, err)
+	bounded.Set("pokeapi:species:mewtwo", "mewtwo species", cache.DefaultExpiration)
+	bounded.Set("pokeapi:species:pikachu", "pikachu species", cache.DefaultExpiration)
+
+	unbounded := cache.NewMemoryCache(time.Hour, time.Hour)
+	unbounded.Set("translation:yoda:some text", "translated text", cache.DefaultExpiration)
+
+	keyring, err := auth.NewKeyring([]auth.Key{{Name: "admin", Secret: testAdminToken, Scopes: []auth.Scope{auth.ScopeAdmin}}})
+	require.NoError(t, err)
+
+	engine := gin.New()
+	server.SetupMiddlewares(engine, nil, middleware.CORSConfig{}, nil)
+	server.RegisterAdminEndpoints(engine, auth.NewAuthenticator(keyring, nil), api.NewCacheAdminHandler(map[string]cache.Cache{
+		"pokeapi":     bounded,
+		"translation": unbounded,
+	}), api.NewAPIKeyAdminHandler(keyring))
+	return engine
+}
+
+// doAdminRequest is a helper function to perform an authorized request against the admin endpoints.
+func doAdminRequest(t *testing.T, engine *gin.Engine, method, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestCacheAdmin_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCacheAdmin(t)
+
+	for _, header := range []string{"", "Bearer wrong"} {
+		req := httptest.NewRequest(http.MethodGet, "/admin/cache/stats", http.NoBody)
+		req.Header.Set("Authorization", header)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestCacheAdmin_ListAndInspect(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCacheAdmin(t)
+
+	// List the keys by prefix
+	w := doAdminRequest(t, engine, http.MethodGet, "/admin/cache/keys?prefix=pokeapi:species:")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var keys models.CacheKeysResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &keys))
+	assert.Equal(t, []models.CacheKey{
+		{Cache: "pokeapi", Key: "pokeapi:species:mewtwo"},
+		{Cache: "pokeapi", Key: "pokeapi:species:pikachu"},
+	}, keys.Keys)
+
+	// Inspect an entry of the unbounded cache
+	w = doAdminRequest(t, engine, http.MethodGet, "/admin/cache/entry?key="+url.QueryEscape("translation:yoda:some text"))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var entry models.CacheEntryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+	assert.Equal(t, "translation", entry.Cache)
+	assert.Equal(t, "translated text", entry.Value)
+	assert.NotNil(t, entry.ExpiresAt)
+	assert.NotEmpty(t, entry.TTL)
+
+	// Inspecting an entry does not count as a cache access
+	w = doAdminRequest(t, engine, http.MethodGet, "/admin/cache/stats")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var stats models.CacheStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, 2, stats["pokeapi"].Entries)
+	assert.Equal(t, uint64(0), stats["translation"].Hits)
+}
+
+func TestCacheAdmin_DeleteAndPurge(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCacheAdmin(t)
+
+	// Delete a single key
+	target := "/admin/cache/entry?key=" + url.QueryEscape("translation:yoda:some text")
+	w := doAdminRequest(t, engine, http.MethodDelete, target)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = doAdminRequest(t, engine, http.MethodDelete, target)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Purge by prefix
+	w = doAdminRequest(t, engine, http.MethodDelete, "/admin/cache/keys?prefix=pokeapi:")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var purge models.CachePurgeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &purge))
+	assert.Equal(t, 2, purge.Deleted)
+
+	// Purging without prefix is rejected
+	w = doAdminRequest(t, engine, http.MethodDelete, "/admin/cache/keys")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/catalog"
+	apperrors "github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/models"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/render"
+)
+
+const (
+	// maxListLimit is the maximum number of Pokemon per page of the listing.
+	maxListLimit = 100
+	// catalogRetryAfter is the delay after which the clients should retry the listing while the catalog is being built.
+	catalogRetryAfter = 30 * time.Second
+)
+
+// CatalogHandler handles the Pokemon listing endpoint, served by the catalog.
+type CatalogHandler struct {
+	catalog *catalog.Catalog
+}
+
+// NewCatalogHandler creates a new CatalogHandler with the given catalog.
+func NewCatalogHandler(c *catalog.Catalog) *CatalogHandler {
+	return &CatalogHandler{catalog: c}
+}
+
+// ListPokemon returns a page of the Pokemon matching the filters given as query parameters.
+// The Link header points to the first and, if any, the next page.
+func (h *CatalogHandler) ListPokemon(c *gin.Context) {
+	query, err := parseListQuery(c)
+	if err != nil {
+		_ = c.Error(httperror.NewHTTPError(http.StatusBadRequest, httperror.CodeInvalidRequest, err.Error()))
+		return
+	}
+
+	page, err := h.catalog.List(query)
+	switch {
+	case errors.Is(err, apperrors.ErrInvalidCursor):
+		_ = c.Error(httperror.NewHTTPError(http.StatusBadRequest, httperror.CodeInvalidRequest,
+			"invalid cursor, it must be the one of the previous page with the same filters and sort"))
+		return
+	case errors.Is(err, apperrors.ErrNotReady):
+		httpErr := httperror.NewHTTPError(http.StatusServiceUnavailable, httperror.CodeCatalogNotReady,
+			"the Pokemon catalog is being built, retry later")
+		httpErr.RetryAfter = catalogRetryAfter
+		_ = c.Error(httpErr)
+		return
+	case err != nil:
+		_ = c.Error(err)
+		return
+	}
+
+	res := models.PokemonListResponse{Total: page.Total, Next: page.Next, Pokemon: make([]models.PokemonSummary, 0, len(page.Entries))}
+	for _, e := range page.Entries {
+		res.Pokemon = append(res.Pokemon, models.PokemonSummary{
+			ID:          e.ID,
+			Name:        e.Name,
+			Generation:  e.Generation,
+			Habitat:     e.Habitat,
+			IsLegendary: e.IsLegendary,
+			Types:       e.Types,
+		})
+	}
+
+	links := []string{pageLink(c.Request.URL, "", "first")}
+	if page.Next != "" {
+		links = append(links, pageLink(c.Request.URL, page.Next, "next"))
+	}
+	c.Header("Link", strings.Join(links, ", "))
+	render.Render(c, http.StatusOK, res)
+}
+
+// invalidQueryError is the error returned by parseListQuery, whose message is sent to the client.
+type invalidQueryError string
+
+func (e invalidQueryError) Error() string { return string(e) }
+
+// parseListQuery returns the catalog query given by the query parameters.
+func parseListQuery(c *gin.Context) (*catalog.Query, error) {
+	query := &catalog.Query{
+		Habitat: strings.ToLower(c.Query("habitat")),
+		Type:    strings.ToLower(c.Query("type")),
+		Sort:    catalog.Sort(strings.ToLower(c.Query("sort"))),
+		Limit:   catalog.DefaultLimit,
+		Cursor:  c.Query("cursor"),
+	}
+
+	if value := c.Query("legendary"); value != "" {
+		legendary, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, invalidQueryError("invalid legendary query parameter, it must be true or false")
+		}
+		query.Legendary = &legendary
+	}
+	if value := c.Query("generation"); value != "" {
+		generation, err := strconv.Atoi(value)
+		if err != nil || generation < 1 {
+			return nil, invalidQueryError("invalid generation query parameter, it must be a positive number")
+		}
+		query.Generation = generation
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			return nil, invalidQueryError("invalid limit query parameter, it must be between 1 and " + strconv.Itoa(maxListLimit))
+		}
+		query.Limit = limit
+	}
+	switch query.Sort {
+	case "":
+		query.Sort = catalog.SortDex
+	case catalog.SortDex, catalog.SortName:
+	default:
+		return nil, invalidQueryError("invalid sort query parameter, it must be dex or name")
+	}
+	return query, nil
+}
+
+// pageLink returns the Link header value of the page with the given cursor (the first page if empty), keeping the other query parameters.
+func pageLink(u *url.URL, cursor, rel string) string {
+	query := u.Query()
+	query.Del("cursor")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	target := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return "<" + target.String() + `>; rel="` + rel + `"`
+}
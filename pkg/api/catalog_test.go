@@ -0,0 +1,151 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/api"
+	"github.com/fra98/pokedex/pkg/catalog"
+	"github.com/fra98/pokedex/pkg/models"
+	"github.com/fra98/pokedex/pkg/server"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+)
+
+// catalogSnapshot is the local snapshot of the test catalog.
+const catalogSnapshot = `{"builtAt": %q, "entries": [
+	{"id": 1, "name": "bulbasaur", "generation": 1, "habitat": "grassland", "isLegendary": false, "types": ["grass", "poison"]},
+	{"id": 7, "name": "squirtle", "generation": 1, "habitat": "waters-edge", "isLegendary": false, "types": ["water"]},
+	{"id": 131, "name": "lapras", "generation": 1, "habitat": "sea", "isLegendary": false, "types": ["water", "ice"]},
+	{"id": 150, "name": "mewtwo", "generation": 1, "habitat": "rare", "isLegendary": true, "types": ["psychic"]},
+	{"id": 382, "name": "kyogre", "generation": 3, "habitat": "sea", "isLegendary": true, "types": ["water"]}
+]}`
+
+// setupCatalog is a helper function to setup a gin engine serving the Pokemon listing.
+// The catalog is loaded from a local snapshot if loaded is true, otherwise it is never built.
+func setupCatalog(t *testing.T, loaded bool) *gin.Engine {
+	t.Helper()
+
+	cfg := &catalog.Config{File: filepath.Join(t.TempDir(), "catalog.json"), RefreshInterval: time.Hour}
+	c := catalog.NewCatalog(nil, cfg)
+	if loaded {
+		snapshot := fmt.Sprintf(catalogSnapshot, time.Now().Format(time.RFC3339))
+		require.NoError(t, os.WriteFile(cfg.File, []byte(snapshot), 0o600))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go c.Run(ctx)
+		require.Eventually(t, func() bool { return c.Check(ctx) == nil }, time.Second, 10*time.Millisecond)
+	}
+
+	engine := gin.New()
+	server.SetupMiddlewares(engine, nil, middleware.CORSConfig{}, nil)
+	server.RegisterEndpoints(engine, api.NewPokemonHandler(staticService{}, nil), api.NewCatalogHandler(c), nil,
+		middleware.HTTPCacheConfig{}, server.RateLimitConfig{}, server.AuthConfig{})
+	return engine
+}
+
+func listPokemon(t *testing.T, engine *gin.Engine, target string) (models.PokemonListResponse, string) {
+	t.Helper()
+
+	w := doRequest(engine, http.MethodGet, target, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var res models.PokemonListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res, w.Header().Get("Link")
+}
+
+func TestListPokemon(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCatalog(t, true)
+
+	res, link := listPokemon(t, engine, "/v1/pokemon?type=Water&legendary=false")
+	assert.Equal(t, 2, res.Total)
+	assert.Empty(t, res.Next)
+	require.Len(t, res.Pokemon, 2)
+	assert.Equal(t, models.PokemonSummary{
+		ID: 131, Name: "lapras", Generation: 1, Habitat: "sea", IsLegendary: false, Types: []string{"water", "ice"},
+	}, res.Pokemon[1])
+	assert.Equal(t, `</v1/pokemon?legendary=false&type=Water>; rel="first"`, link)
+
+	// The pages are walked following the Link header, keeping the filters
+	res, link = listPokemon(t, engine, "/v1/pokemon?habitat=sea&sort=name&limit=1")
+	assert.Equal(t, "kyogre", res.Pokemon[0].Name)
+	assert.Equal(t, 2, res.Total)
+	first, next, found := strings.Cut(link, ", ")
+	require.True(t, found)
+	assert.Equal(t, `</v1/pokemon?habitat=sea&limit=1&sort=name>; rel="first"`, first)
+	require.True(t, strings.HasSuffix(next, `>; rel="next"`))
+
+	res, link = listPokemon(t, engine, strings.TrimSuffix(strings.TrimPrefix(next, "<"), `>; rel="next"`))
+	assert.Equal(t, "lapras", res.Pokemon[0].Name)
+	assert.NotContains(t, link, `rel="next"`)
+
+	// The listing is also available as CSV
+	w := doRequest(engine, http.MethodGet, "/v1/pokemon?generation=3&format=csv", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id,name,generation,habitat,isLegendary,types\n382,kyogre,3,sea,true,water\n", w.Body.String())
+}
+
+func TestListPokemon_Problems(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCatalog(t, true)
+
+	testCases := map[string]struct {
+		target string
+		status int
+		code   string
+	}{
+		"limit_zero":     {target: "/v1/pokemon?limit=0", status: http.StatusBadRequest, code: httperror.CodeInvalidRequest},
+		"limit_too_high": {target: "/v1/pokemon?limit=101", status: http.StatusBadRequest, code: httperror.CodeInvalidRequest},
+		"legendary":      {target: "/v1/pokemon?legendary=maybe", status: http.StatusBadRequest, code: httperror.CodeInvalidRequest},
+		"generation":     {target: "/v1/pokemon?generation=first", status: http.StatusBadRequest, code: httperror.CodeInvalidRequest},
+		"sort":           {target: "/v1/pokemon?sort=weight", status: http.StatusBadRequest, code: httperror.CodeInvalidRequest},
+		"cursor":         {target: "/v1/pokemon?cursor=garbage", status: http.StatusBadRequest, code: httperror.CodeInvalidRequest},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			w := doRequest(engine, http.MethodGet, tc.target, nil)
+			require.Equal(t, tc.status, w.Code)
+			var problem httperror.HTTPError
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tc.code, problem.Code)
+		})
+	}
+
+	// The cursor of a page is rejected with other filters
+	_, link := listPokemon(t, engine, "/v1/pokemon?type=water&limit=1")
+	_, next, _ := strings.Cut(link, ", ")
+	target := strings.Replace(strings.TrimSuffix(strings.TrimPrefix(next, "<"), `>; rel="next"`), "type=water", "type=ice", 1)
+	w := doRequest(engine, http.MethodGet, target, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListPokemon_NotReady(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCatalog(t, false)
+
+	w := doRequest(engine, http.MethodGet, "/v1/pokemon", nil)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	var problem httperror.HTTPError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, httperror.CodeCatalogNotReady, problem.Code)
+}
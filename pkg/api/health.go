@@ -4,12 +4,36 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/health"
 )
 
-// IsHealthy returns a 200 OK response if the server is healthy.
+// IsHealthy returns a 200 OK response if the server is healthy, i.e., the process is up and serving requests.
+// It does not check the dependencies, which are checked by the readiness endpoint instead.
 func IsHealthy(ctx *gin.Context) {
 	res := make(map[string]interface{})
 	res["status"] = 200
 	res["healthy"] = "OK"
 	ctx.JSON(http.StatusOK, res)
 }
+
+// HealthHandler handles the readiness endpoint.
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+// NewHealthHandler creates a new HealthHandler running the checks of the given registry.
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{registry: registry}
+}
+
+// IsReady returns a 200 OK response if all the critical readiness checks pass, 503 Service Unavailable otherwise.
+// The response reports the result of each check, so that failing dependencies can be identified.
+func (h *HealthHandler) IsReady(ctx *gin.Context) {
+	report := h.registry.Check(ctx.Request.Context())
+	if report.Status == health.StatusFail {
+		ctx.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
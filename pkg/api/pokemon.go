@@ -1,60 +1,128 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	apperrors "github.com/fra98/pokedex/pkg/errors"
 	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+	"github.com/fra98/pokedex/pkg/server/render"
 	"github.com/fra98/pokedex/pkg/service"
+	"github.com/fra98/pokedex/pkg/suggest"
 )
 
 // PokemonHandler handles the Pokemon API endpoints.
 type PokemonHandler struct {
 	pokemonService service.Pokemon
+	suggester      *suggest.Suggester
 }
 
 // NewPokemonHandler creates a new PokemonHandler with the given PokemonService.
-func NewPokemonHandler(pokemonService service.Pokemon) *PokemonHandler {
-	return &PokemonHandler{pokemonService: pokemonService}
+// If the suggester is provided, the names of the Pokemon not found are answered with the closest existing ones.
+func NewPokemonHandler(pokemonService service.Pokemon, suggester *suggest.Suggester) *PokemonHandler {
+	return &PokemonHandler{pokemonService: pokemonService, suggester: suggester}
 }
 
 // GetPokemon returns the information of a Pokemon given its name.
 func (h *PokemonHandler) GetPokemon(c *gin.Context) {
 	name := c.Param("name")
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("pokemon.name", name))
 
 	pokemon, err := h.pokemonService.GetPokemonInfo(c.Request.Context(), name)
 	if err != nil {
-		err := httperror.NewHTTPError("unable to retrieve pokemon info", getStatusCode(err))
-		_ = c.Error(err)
+		_ = c.Error(h.newHTTPError(c.Request.Context(), name, err))
 		return
 	}
 
-	c.JSON(http.StatusOK, pokemon)
+	render.Render(c, http.StatusOK, pokemon)
 }
 
 // GetTranslatedPokemon returns the information of a Pokemon given its name with a translated description.
 func (h *PokemonHandler) GetTranslatedPokemon(c *gin.Context) {
 	name := c.Param("name")
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("pokemon.name", name))
 
 	pokemon, err := h.pokemonService.GetTranslatedPokemonInfo(c.Request.Context(), name)
 	if err != nil {
-		err := httperror.NewHTTPError("unable to retrieve translated pokemon info", getStatusCode(err))
-		_ = c.Error(err)
+		_ = c.Error(h.newHTTPError(c.Request.Context(), name, err))
 		return
 	}
 
-	c.JSON(http.StatusOK, pokemon)
+	// Responses falling back to the original description should be retried sooner by the clients
+	if pokemon.TranslationFallback != "" {
+		middleware.MarkDegraded(c)
+	}
+
+	render.Render(c, http.StatusOK, pokemon)
+}
+
+// problem describes how a service error is answered, given the sentinel error it wraps.
+type problem struct {
+	err    error
+	status int
+	code   string
+	// detail is the explanation of the problem, formatted with the name of the Pokemon.
+	detail string
+}
+
+// problems lists the service errors matched through the wrap chain, in order of precedence.
+// Internal server errors are handled by the ErrorHandler middleware.
+var problems = []problem{
+	{apperrors.ErrResourceNotFound, http.StatusNotFound, httperror.CodePokemonNotFound, "pokemon %q does not exist"},
+	{apperrors.ErrDescriptionNotFound, http.StatusUnprocessableEntity, httperror.CodeDescriptionNotFound,
+		"pokemon %q has no description in English"},
+	{context.Canceled, httperror.StatusClientClosedRequest, httperror.CodeClientClosedRequest,
+		"the request for pokemon %q was canceled by the client"},
+	{apperrors.ErrUpstreamTimeout, http.StatusGatewayTimeout, httperror.CodeUpstreamTimeout,
+		"the upstream API did not answer in time, unable to retrieve pokemon %q"},
+	{apperrors.ErrInvalidResponse, http.StatusBadGateway, httperror.CodeUpstreamBadResponse,
+		"the upstream API answered with an invalid payload, unable to retrieve pokemon %q"},
+	{apperrors.ErrRateLimitExceeded, http.StatusServiceUnavailable, httperror.CodeUpstreamRateLimited,
+		"the upstream API is rate limiting the requests, unable to retrieve pokemon %q"},
+	{apperrors.ErrBulkheadFull, http.StatusServiceUnavailable, httperror.CodeUpstreamBusy,
+		"the upstream API has too many requests in flight, unable to retrieve pokemon %q"},
+	{apperrors.ErrCircuitOpen, http.StatusServiceUnavailable, httperror.CodeUpstreamUnavailable,
+		"the upstream API is failing and temporarily not contacted, unable to retrieve pokemon %q"},
+	{apperrors.ErrFailedRequest, http.StatusBadGateway, httperror.CodeUpstreamFailed,
+		"the upstream API answered with an error, unable to retrieve pokemon %q"},
 }
 
-func getStatusCode(err error) int {
-	switch {
-	case errors.Is(err, apperrors.ErrResourceNotFound):
-		return http.StatusNotFound
-	default:
-		return http.StatusServiceUnavailable
+// unknownProblem answers the service errors not matching any of the known problems, e.g., the upstream API being unreachable.
+var unknownProblem = problem{
+	status: http.StatusServiceUnavailable,
+	code:   httperror.CodeUpstreamUnavailable,
+	detail: "the upstream API is unreachable, unable to retrieve pokemon %q",
+}
+
+// newHTTPError returns the HTTP error corresponding to the service error for the given Pokemon.
+// If the Pokemon is not found, the closest existing names are suggested, if any.
+// If the upstream circuit breaker is open, the client is told when to retry.
+func (h *PokemonHandler) newHTTPError(ctx context.Context, name string, err error) httperror.HTTPError {
+	p := unknownProblem
+	for _, candidate := range problems {
+		if errors.Is(err, candidate.err) {
+			p = candidate
+			break
+		}
+	}
+	httpErr := httperror.NewHTTPError(p.status, p.code, fmt.Sprintf(p.detail, name))
+
+	if h.suggester != nil && errors.Is(err, apperrors.ErrResourceNotFound) {
+		if httpErr.Suggestions = h.suggester.Suggest(ctx, name); len(httpErr.Suggestions) > 0 {
+			httpErr.Detail += fmt.Sprintf(", did you mean %q?", httpErr.Suggestions[0])
+		}
+	}
+
+	var circuitOpenErr *apperrors.CircuitOpenError
+	if errors.As(err, &circuitOpenErr) {
+		httpErr.RetryAfter = circuitOpenErr.RetryAfter
 	}
-	// Internal server errors are handled by the ErrorHandler middleware
+	return httpErr
 }
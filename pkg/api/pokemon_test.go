@@ -0,0 +1,179 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/api"
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+	apperrors "github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/models"
+	"github.com/fra98/pokedex/pkg/server"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+	"github.com/fra98/pokedex/pkg/suggest"
+)
+
+// failingService is a service.Pokemon failing every request with the given error.
+type failingService struct {
+	err error
+}
+
+func (s failingService) GetPokemonInfo(_ context.Context, _ string) (*models.PokemonResponse, error) {
+	return nil, s.err
+}
+
+func (s failingService) GetTranslatedPokemonInfo(_ context.Context, _ string) (*models.PokemonResponse, error) {
+	return nil, s.err
+}
+
+func TestGetPokemon_Problems(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err        error
+		status     int
+		code       string
+		retryAfter string
+	}{
+		"not_found": {
+			err: fmt.Errorf("unable to retrieve pokemon species: %w",
+				apperrors.NewUpstreamError("pokeapi", http.StatusNotFound, apperrors.ErrResourceNotFound)),
+			status: http.StatusNotFound,
+			code:   httperror.CodePokemonNotFound,
+		},
+		"no_description": {
+			err:    fmt.Errorf("unable to extract English description: %w", apperrors.ErrDescriptionNotFound),
+			status: http.StatusUnprocessableEntity,
+			code:   httperror.CodeDescriptionNotFound,
+		},
+		"canceled": {
+			err:    fmt.Errorf("failed to send request: %w", apperrors.NewUpstreamError("pokeapi", 0, context.Canceled)),
+			status: httperror.StatusClientClosedRequest,
+			code:   httperror.CodeClientClosedRequest,
+		},
+		"timeout": {
+			err:    fmt.Errorf("failed to send request: %w", apperrors.NewUpstreamError("pokeapi", 0, context.DeadlineExceeded)),
+			status: http.StatusGatewayTimeout,
+			code:   httperror.CodeUpstreamTimeout,
+		},
+		"bad_payload": {
+			err: fmt.Errorf("failed to decode response: %w",
+				apperrors.NewUpstreamError("pokeapi", http.StatusOK, fmt.Errorf("%w: %w", apperrors.ErrInvalidResponse, io.ErrUnexpectedEOF))),
+			status: http.StatusBadGateway,
+			code:   httperror.CodeUpstreamBadResponse,
+		},
+		"upstream_failure": {
+			err:    fmt.Errorf("unexpected response: %w", apperrors.NewUpstreamError("pokeapi", http.StatusInternalServerError, apperrors.ErrFailedRequest)),
+			status: http.StatusBadGateway,
+			code:   httperror.CodeUpstreamFailed,
+		},
+		"rate_limited": {
+			err:    fmt.Errorf("unable to retrieve pokemon species: %w", apperrors.ErrRateLimitExceeded),
+			status: http.StatusServiceUnavailable,
+			code:   httperror.CodeUpstreamRateLimited,
+		},
+		"busy": {
+			err:    fmt.Errorf("unable to retrieve pokemon species: %w", apperrors.ErrBulkheadFull),
+			status: http.StatusServiceUnavailable,
+			code:   httperror.CodeUpstreamBusy,
+		},
+		"circuit_open": {
+			err: fmt.Errorf("unable to retrieve pokemon species: %w",
+				&apperrors.CircuitOpenError{Upstream: "pokeapi", RetryAfter: 2500 * time.Millisecond}),
+			status:     http.StatusServiceUnavailable,
+			code:       httperror.CodeUpstreamUnavailable,
+			retryAfter: "3",
+		},
+		"unreachable": {
+			err:    fmt.Errorf("failed to send request: %w", apperrors.NewUpstreamError("pokeapi", 0, syscall.ECONNREFUSED)),
+			status: http.StatusServiceUnavailable,
+			code:   httperror.CodeUpstreamUnavailable,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			engine := gin.New()
+			server.SetupMiddlewares(engine, nil, middleware.CORSConfig{}, nil)
+			server.RegisterEndpoints(engine, api.NewPokemonHandler(failingService{err: tc.err}, nil), nil, nil,
+				middleware.HTTPCacheConfig{}, server.RateLimitConfig{}, server.AuthConfig{})
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/pokemon/mewtwo", http.NoBody))
+
+			require.Equal(t, tc.status, w.Code)
+			assert.Equal(t, httperror.ContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tc.retryAfter, w.Header().Get("Retry-After"))
+
+			var problem httperror.HTTPError
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tc.code, problem.Code)
+			assert.Equal(t, tc.status, problem.Status)
+			assert.NotEmpty(t, problem.Type)
+			assert.NotEmpty(t, problem.Title)
+			assert.Contains(t, problem.Detail, `"mewtwo"`)
+			assert.Equal(t, "/v1/pokemon/mewtwo", problem.Instance)
+		})
+	}
+}
+
+func TestGetPokemon_Suggestions(t *testing.T) {
+	t.Parallel()
+
+	pokeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := io.WriteString(w, `{"count": 4, "results": [{"name": "mew"}, {"name": "mewtwo"}, {"name": "pikachu"}, {"name": "raichu"}]}`)
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(pokeServer.Close)
+
+	suggester := suggest.NewSuggester(suggest.NewSpeciesList(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), &suggest.ListConfig{}), &suggest.Config{})
+	notFound := fmt.Errorf("unable to retrieve pokemon species: %w",
+		apperrors.NewUpstreamError("pokeapi", http.StatusNotFound, apperrors.ErrResourceNotFound))
+
+	engine := gin.New()
+	server.SetupMiddlewares(engine, nil, middleware.CORSConfig{}, nil)
+	server.RegisterEndpoints(engine, api.NewPokemonHandler(failingService{err: notFound}, suggester), nil, nil,
+		middleware.HTTPCacheConfig{}, server.RateLimitConfig{}, server.AuthConfig{})
+
+	testCases := map[string]struct {
+		name        string
+		suggestions []string
+	}{
+		"typo":    {name: "pikachuu", suggestions: []string{"pikachu"}},
+		"close":   {name: "mewto", suggestions: []string{"mewtwo", "mew"}},
+		"unknown": {name: "missingno", suggestions: nil},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/pokemon/"+tc.name, http.NoBody))
+			require.Equal(t, http.StatusNotFound, w.Code)
+
+			var problem httperror.HTTPError
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, httperror.CodePokemonNotFound, problem.Code)
+			assert.Equal(t, tc.suggestions, problem.Suggestions)
+			if len(tc.suggestions) > 0 {
+				assert.Contains(t, problem.Detail, fmt.Sprintf("did you mean %q?", tc.suggestions[0]))
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/models"
+	"github.com/fra98/pokedex/pkg/search"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/render"
+)
+
+const (
+	// defaultSearchLimit and maxSearchLimit are the default and maximum number of search results.
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	// maxQueryLength is the maximum length of a search query, in characters.
+	maxQueryLength = 100
+)
+
+// SearchHandler handles the search endpoint, served by the search index.
+type SearchHandler struct {
+	index *search.Index
+}
+
+// NewSearchHandler creates a new SearchHandler with the given search index.
+func NewSearchHandler(index *search.Index) *SearchHandler {
+	return &SearchHandler{index: index}
+}
+
+// Search returns the Pokemon whose name or description matches the query given as q query parameter, with the best matches first.
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		_ = c.Error(httperror.NewHTTPError(http.StatusBadRequest, httperror.CodeInvalidRequest, "missing q query parameter"))
+		return
+	}
+	if utf8.RuneCountInString(query) > maxQueryLength {
+		_ = c.Error(httperror.NewHTTPError(http.StatusBadRequest, httperror.CodeInvalidRequest,
+			"invalid q query parameter, it must be at most "+strconv.Itoa(maxQueryLength)+" characters long"))
+		return
+	}
+
+	limit := defaultSearchLimit
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxSearchLimit {
+			_ = c.Error(httperror.NewHTTPError(http.StatusBadRequest, httperror.CodeInvalidRequest,
+				"invalid limit query parameter, it must be between 1 and "+strconv.Itoa(maxSearchLimit)))
+			return
+		}
+	}
+
+	result := h.index.Search(query, limit)
+	res := models.SearchResponse{Query: query, Total: result.Total, Results: make([]models.SearchResult, 0, len(result.Hits))}
+	for _, hit := range result.Hits {
+		res.Results = append(res.Results, models.SearchResult{
+			Name:      hit.Name,
+			Score:     math.Round(hit.Score*1000) / 1000,
+			NameMatch: string(hit.NameMatch),
+			Highlight: hit.Highlight,
+			Snippet:   hit.Snippet,
+		})
+	}
+	render.Render(c, http.StatusOK, res)
+}
@@ -0,0 +1,72 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/api"
+	"github.com/fra98/pokedex/pkg/models"
+	"github.com/fra98/pokedex/pkg/search"
+	"github.com/fra98/pokedex/pkg/server"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+)
+
+// setupSearch is a helper function to setup a gin engine serving the search of a few indexed species.
+func setupSearch() *gin.Engine {
+	index := search.NewIndex()
+	index.Add("pikachu", []string{"When several of these POKéMON gather, their electricity could build and cause lightning storms."})
+	index.Add("pichu", []string{"It is not yet skilled at storing electricity. It may send out a jolt if amused or startled."})
+	index.Add("mewtwo", []string{"It was created by a scientist after years of horrific gene splicing and DNA engineering experiments."})
+
+	engine := gin.New()
+	server.SetupMiddlewares(engine, nil, middleware.CORSConfig{}, nil)
+	server.RegisterEndpoints(engine, api.NewPokemonHandler(staticService{}, nil), nil, api.NewSearchHandler(index),
+		middleware.HTTPCacheConfig{}, server.RateLimitConfig{}, server.AuthConfig{})
+	return engine
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	engine := setupSearch()
+
+	w := doRequest(engine, http.MethodGet, "/v1/search?q=pikahcu", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var res models.SearchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "pikahcu", res.Query)
+	assert.Equal(t, 1, res.Total)
+	require.Len(t, res.Results, 1)
+	assert.Equal(t, "pikachu", res.Results[0].Name)
+	assert.Equal(t, string(search.MatchFuzzy), res.Results[0].NameMatch)
+	assert.Equal(t, "<em>pikachu</em>", res.Results[0].Highlight)
+
+	w = doRequest(engine, http.MethodGet, "/v1/search?q=electricity&limit=1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	res = models.SearchResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 2, res.Total)
+	require.Len(t, res.Results, 1)
+	assert.Contains(t, res.Results[0].Snippet, "<em>electricity</em>")
+	assert.Empty(t, res.Results[0].NameMatch)
+}
+
+func TestSearch_InvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	engine := setupSearch()
+
+	for _, target := range []string{"/v1/search", "/v1/search?q=%20", "/v1/search?q=pika&limit=0", "/v1/search?q=pika&limit=51"} {
+		w := doRequest(engine, http.MethodGet, target, nil)
+		require.Equal(t, http.StatusBadRequest, w.Code, target)
+		var problem httperror.HTTPError
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, httperror.CodeInvalidRequest, problem.Code)
+	}
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// Authenticator authorizes the requests carrying either an API key of the keyring or a JWT verified by the token verifier.
+// The credentials shaped as JWTs (three dot-separated parts) are verified as tokens, the others are looked up as API keys.
+type Authenticator struct {
+	keyring  *Keyring
+	verifier *TokenVerifier
+}
+
+// NewAuthenticator returns a new Authenticator. Either the keyring or the verifier may be nil, disabling the respective credentials.
+func NewAuthenticator(keyring *Keyring, verifier *TokenVerifier) *Authenticator {
+	return &Authenticator{keyring: keyring, verifier: verifier}
+}
+
+// Keyring returns the keyring of the API keys, if any.
+func (a *Authenticator) Keyring() *Keyring {
+	return a.keyring
+}
+
+// HasScope returns whether the given scope can be granted, by an API key or by a token.
+func (a *Authenticator) HasScope(scope Scope) bool {
+	return a.verifier != nil || (a.keyring != nil && a.keyring.HasScope(scope))
+}
+
+// Authorize authorizes a request carrying the given credential on a route requiring the given scope.
+// It returns the ID of the client: the name of the API key, or the subject of the token prefixed by "jwt:".
+// The error wraps ErrInvalidAPIKey, ErrInvalidToken, ErrInsufficientScope or ErrQuotaExceeded.
+func (a *Authenticator) Authorize(ctx context.Context, credential string, scope Scope) (string, error) {
+	if a.verifier != nil && strings.Count(credential, ".") == 2 {
+		subject, err := a.verifier.Authorize(ctx, credential, scope)
+		if err != nil {
+			return "", err
+		}
+		return "jwt:" + subject, nil
+	}
+
+	if a.keyring == nil {
+		return "", errors.ErrInvalidAPIKey
+	}
+	return a.keyring.Authorize(credential, scope)
+}
@@ -0,0 +1,43 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/auth"
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+func TestAuthenticator_Authorize(t *testing.T) {
+	t.Parallel()
+
+	key := newECKey(t, "ec-1")
+	verifier := newVerifier(t, auth.TokenConfig{}, auth.JWKSConfig{URL: newJWKSServer(t, key).URL})
+	keyring, err := auth.NewKeyring([]auth.Key{{Name: "partner", Secret: "s3cret", Scopes: []auth.Scope{auth.ScopeRead}}})
+	require.NoError(t, err)
+	token := sign(t, key, claims("read"))
+
+	// API keys and tokens are both accepted, the tokens identified by their subject
+	authenticator := auth.NewAuthenticator(keyring, verifier)
+	clientID, err := authenticator.Authorize(t.Context(), "s3cret", auth.ScopeRead)
+	require.NoError(t, err)
+	assert.Equal(t, "partner", clientID)
+	clientID, err = authenticator.Authorize(t.Context(), token, auth.ScopeRead)
+	require.NoError(t, err)
+	assert.Equal(t, "jwt:partner", clientID)
+	assert.True(t, authenticator.HasScope(auth.ScopeAdmin))
+
+	// Without verifier, the tokens are looked up as API keys
+	authenticator = auth.NewAuthenticator(keyring, nil)
+	_, err = authenticator.Authorize(t.Context(), token, auth.ScopeRead)
+	require.ErrorIs(t, err, errors.ErrInvalidAPIKey)
+	assert.False(t, authenticator.HasScope(auth.ScopeAdmin))
+
+	// Without keyring, the API keys are rejected
+	authenticator = auth.NewAuthenticator(nil, verifier)
+	_, err = authenticator.Authorize(t.Context(), "s3cret", auth.ScopeRead)
+	require.ErrorIs(t, err, errors.ErrInvalidAPIKey)
+	assert.Nil(t, authenticator.Keyring())
+}
@@ -0,0 +1,2 @@
+// Package auth provides the API keys authorizing the clients, with their scopes and daily quotas.
+package auth
@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// JWKSConfig contains the configuration of a JSON Web Key Set.
+type JWKSConfig struct {
+	// URL is the URL the key set is fetched from, alternative to File.
+	URL string
+	// File is the file the key set is read from, alternative to URL.
+	File string
+	// RefreshInterval is the age after which the key set is loaded again (0 disables the periodic refresh).
+	RefreshInterval time.Duration
+	// MinRefreshInterval is the minimum interval between two loads, either periodic or triggered by tokens signed
+	// with unknown keys, so that forged tokens cannot flood the identity provider, nor a failing one be queried on every request.
+	MinRefreshInterval time.Duration
+	// HTTPClient is the client fetching the key set from the URL, http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+// jwk is a JSON Web Key, as defined by RFC 7517. Only the public key members are used.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC and OKP keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// verificationKey is a public key verifying the token signatures, along with the algorithm it is restricted to, if any.
+type verificationKey struct {
+	key any
+	alg string
+}
+
+// JWKS is a JSON Web Key Set loaded from a file or a URL, cached and refreshed to follow the key rotations.
+// The key set is refreshed in background when older than the refresh interval, serving the current one meanwhile,
+// and loaded again when a token is signed with an unknown key. Both loads are at most one per minimum refresh interval,
+// and run without holding the lock, so that the other requests are not blocked by a slow identity provider.
+type JWKS struct {
+	cfg JWKSConfig
+
+	mu          sync.Mutex
+	keys        map[string]verificationKey
+	loadedAt    time.Time
+	attemptedAt time.Time
+	refreshing  bool
+}
+
+// NewJWKS returns a new JWKS, loading the key set.
+func NewJWKS(ctx context.Context, cfg JWKSConfig) (*JWKS, error) {
+	if (cfg.URL == "") == (cfg.File == "") {
+		return nil, fmt.Errorf("exactly one of JWKS URL and file is required: %w", errors.ErrInvalidConfiguration)
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+
+	j := &JWKS{cfg: cfg, attemptedAt: time.Now()}
+	keys, err := j.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	j.keys, j.loadedAt = keys, j.attemptedAt
+	return j, nil
+}
+
+// key returns the key with the given ID, refreshing the key set in background if stale,
+// or loading it again if the key is unknown. If the ID is empty, the key set must contain a single key.
+func (j *JWKS) key(ctx context.Context, kid string) (verificationKey, error) {
+	j.mu.Lock()
+	if j.cfg.RefreshInterval > 0 && time.Since(j.loadedAt) >= j.cfg.RefreshInterval && !j.refreshing && j.attempt() {
+		j.refreshing = true
+		go func() {
+			j.reload(context.WithoutCancel(ctx))
+			j.mu.Lock()
+			defer j.mu.Unlock()
+			j.refreshing = false
+		}()
+	}
+	key, found := j.lookup(kid)
+	// The key may have been rotated since the last load
+	reload := !found && j.attempt()
+	j.mu.Unlock()
+
+	if found {
+		return key, nil
+	}
+	if reload {
+		j.reload(ctx)
+		j.mu.Lock()
+		key, found = j.lookup(kid)
+		j.mu.Unlock()
+		if found {
+			return key, nil
+		}
+	}
+	return verificationKey{}, fmt.Errorf("unknown signing key %q: %w", kid, errors.ErrInvalidToken)
+}
+
+// attempt records a load attempt and returns true if the minimum refresh interval elapsed since the previous one,
+// false otherwise. It must be called with the lock held.
+func (j *JWKS) attempt() bool {
+	now := time.Now()
+	if now.Sub(j.attemptedAt) < j.cfg.MinRefreshInterval {
+		return false
+	}
+	j.attemptedAt = now
+	return true
+}
+
+// lookup returns the key with the given ID. It must be called with the lock held.
+func (j *JWKS) lookup(kid string) (verificationKey, bool) {
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+	key, found := j.keys[kid]
+	return key, found
+}
+
+// reload loads the key set without holding the lock, replacing the current one on success and keeping it on failure.
+func (j *JWKS) reload(ctx context.Context) {
+	keys, err := j.load(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to refresh the JWKS, keeping the current keys", "error", err)
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.keys, j.loadedAt = keys, time.Now()
+}
+
+// load reads and parses the key set, returning its keys by ID.
+func (j *JWKS) load(ctx context.Context) (map[string]verificationKey, error) {
+	data, err := j.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS (%w): %w", err, errors.ErrInvalidConfiguration)
+	}
+
+	keys := make(map[string]verificationKey, len(set.Keys))
+	for i := range set.Keys {
+		k := &set.Keys[i]
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping unsupported JWK", "kid", k.Kid, "error", err)
+			continue
+		}
+		keys[k.Kid] = verificationKey{key: pub, alg: k.Alg}
+	}
+	return keys, nil
+}
+
+// read returns the content of the key set, from the file or the URL.
+func (j *JWKS) read(ctx context.Context) ([]byte, error) {
+	if j.cfg.File != "" {
+		data, err := os.ReadFile(j.cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.cfg.URL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	resp, err := j.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS (code: %d): %w", resp.StatusCode, errors.ErrFailedRequest)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS response: %w", err)
+	}
+	return data, nil
+}
+
+// publicKey returns the public key represented by the JWK.
+func (k *jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("RSA exponent too large: %w", errors.ErrInvalidConfiguration)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q: %w", k.Crv, errors.ErrInvalidConfiguration)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		if _, err := pub.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid EC point (%w): %w", err, errors.ErrInvalidConfiguration)
+		}
+		return pub, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || k.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key: %w", errors.ErrInvalidConfiguration)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q: %w", k.Kty, errors.ErrInvalidConfiguration)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid key parameter: %w", errors.ErrInvalidConfiguration)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
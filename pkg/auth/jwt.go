@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// DefaultScopeClaim is the claim carrying the scopes of the tokens, as space-separated string (RFC 8693) or array.
+const DefaultScopeClaim = "scope"
+
+// validMethods are the accepted signing algorithms, all asymmetric.
+var validMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// TokenConfig contains the configuration of the JWT validation.
+type TokenConfig struct {
+	// Issuer is the expected iss claim.
+	Issuer string
+	// Audience is the expected aud claim.
+	Audience string
+	// ScopeClaim is the claim carrying the scopes, DefaultScopeClaim if empty.
+	ScopeClaim string
+	// ScopePrefix is the prefix of the scopes granted by the identity provider (e.g., "pokedex:"), stripped before matching.
+	ScopePrefix string
+	// Leeway is the tolerated clock skew when validating the time-based claims.
+	Leeway time.Duration
+}
+
+// TokenVerifier authorizes the requests carrying a JWT issued by the identity provider,
+// verifying its signature against the JWKS and mapping its scope claim to the scopes of the API.
+type TokenVerifier struct {
+	cfg    TokenConfig
+	jwks   *JWKS
+	parser *jwt.Parser
+}
+
+// NewTokenVerifier returns a new TokenVerifier validating the tokens against the given JWKS.
+// The issuer and the audience are required.
+func NewTokenVerifier(cfg TokenConfig, jwks *JWKS) (*TokenVerifier, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, fmt.Errorf("JWT issuer and audience are required: %w", errors.ErrInvalidConfiguration)
+	}
+	if cfg.ScopeClaim == "" {
+		cfg.ScopeClaim = DefaultScopeClaim
+	}
+
+	return &TokenVerifier{
+		cfg:  cfg,
+		jwks: jwks,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(validMethods),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(cfg.Leeway),
+		),
+	}, nil
+}
+
+// Authorize authorizes a request carrying the given token on a route requiring the given scope.
+// It returns the subject of the token. The error wraps ErrInvalidToken or ErrInsufficientScope.
+func (v *TokenVerifier) Authorize(ctx context.Context, token string, scope Scope) (string, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.jwks.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if key.alg != "" && key.alg != t.Method.Alg() {
+			return nil, fmt.Errorf("signing key %q restricted to %s: %w", kid, key.alg, errors.ErrInvalidToken)
+		}
+		return key.key, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errors.ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return "", fmt.Errorf("token without subject: %w", errors.ErrInvalidToken)
+	}
+	if !v.hasScope(claims, scope) {
+		return "", fmt.Errorf("token of %q without %q scope: %w", subject, scope, errors.ErrInsufficientScope)
+	}
+	return subject, nil
+}
+
+// hasScope returns whether the scope claim grants the given scope.
+func (v *TokenVerifier) hasScope(claims jwt.MapClaims, scope Scope) bool {
+	var granted []string
+	switch value := claims[v.cfg.ScopeClaim].(type) {
+	case string:
+		granted = strings.Fields(value)
+	case []any:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				granted = append(granted, s)
+			}
+		}
+	}
+
+	for _, s := range granted {
+		if name, found := strings.CutPrefix(s, v.cfg.ScopePrefix); found && Scope(name) == scope {
+			return true
+		}
+	}
+	return false
+}
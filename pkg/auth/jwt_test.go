@@ -0,0 +1,327 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/auth"
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+const (
+	testIssuer   = "https://idp.example.com/"
+	testAudience = "pokedex"
+)
+
+// signingKey is a locally generated key signing the test tokens.
+type signingKey struct {
+	kid    string
+	method jwt.SigningMethod
+	key    any
+	jwk    map[string]string
+}
+
+func newRSAKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return signingKey{kid: kid, method: jwt.SigningMethodRS256, key: key, jwk: map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+		"n": encode(key.N), "e": encode(big.NewInt(int64(key.E))),
+	}}
+}
+
+func newECKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return signingKey{kid: kid, method: jwt.SigningMethodES256, key: key, jwk: map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256", "x": encode(key.X), "y": encode(key.Y),
+	}}
+}
+
+func encode(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+// jwksJSON returns the key set of the public keys, in the JSON format.
+func jwksJSON(t *testing.T, keys ...signingKey) []byte {
+	t.Helper()
+
+	set := struct {
+		Keys []map[string]string `json:"keys"`
+	}{Keys: []map[string]string{}}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, k.jwk)
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	return data
+}
+
+// jwksServer is an in-process identity provider serving the key set, which can be rotated.
+type jwksServer struct {
+	*httptest.Server
+	jwks     atomic.Pointer[[]byte]
+	requests atomic.Int64
+}
+
+func newJWKSServer(t *testing.T, keys ...signingKey) *jwksServer {
+	t.Helper()
+
+	s := &jwksServer{}
+	s.rotate(t, keys...)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(*s.jwks.Load())
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) rotate(t *testing.T, keys ...signingKey) {
+	t.Helper()
+
+	data := jwksJSON(t, keys...)
+	s.jwks.Store(&data)
+}
+
+// claims returns valid claims granting the given scopes.
+func claims(scope string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   "partner",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": scope,
+	}
+}
+
+func sign(t *testing.T, key signingKey, c jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(key.method, c)
+	if key.kid != "" {
+		token.Header["kid"] = key.kid
+	}
+	signed, err := token.SignedString(key.key)
+	require.NoError(t, err)
+	return signed
+}
+
+func newVerifier(t *testing.T, cfg auth.TokenConfig, jwksCfg auth.JWKSConfig) *auth.TokenVerifier {
+	t.Helper()
+
+	jwks, err := auth.NewJWKS(t.Context(), jwksCfg)
+	require.NoError(t, err)
+	cfg.Issuer, cfg.Audience = testIssuer, testAudience
+	verifier, err := auth.NewTokenVerifier(cfg, jwks)
+	require.NoError(t, err)
+	return verifier
+}
+
+func TestTokenVerifier_Authorize(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, ecKey := newRSAKey(t, "rsa-1"), newECKey(t, "ec-1")
+	server := newJWKSServer(t, rsaKey, ecKey)
+	verifier := newVerifier(t, auth.TokenConfig{}, auth.JWKSConfig{URL: server.URL})
+
+	withClaim := func(name string, value any) jwt.MapClaims {
+		c := claims("read translate")
+		c[name] = value
+		return c
+	}
+	withoutClaim := func(name string) jwt.MapClaims {
+		c := claims("read translate")
+		delete(c, name)
+		return c
+	}
+	// The RSA key restricted to RS256 cannot verify tokens signed with PS256
+	psKey := rsaKey
+	psKey.method = jwt.SigningMethodPS256
+
+	testCases := map[string]struct {
+		token    string
+		scope    auth.Scope
+		expected error
+	}{
+		"rsa":             {token: sign(t, rsaKey, claims("read translate")), scope: auth.ScopeTranslate},
+		"ec":              {token: sign(t, ecKey, claims("read")), scope: auth.ScopeRead},
+		"scope_array":     {token: sign(t, ecKey, withClaim("scope", []string{"read", "admin"})), scope: auth.ScopeAdmin},
+		"missing_scope":   {token: sign(t, rsaKey, claims("read")), scope: auth.ScopeAdmin, expected: errors.ErrInsufficientScope},
+		"wrong_audience":  {token: sign(t, rsaKey, withClaim("aud", "other")), scope: auth.ScopeRead, expected: errors.ErrInvalidToken},
+		"wrong_issuer":    {token: sign(t, rsaKey, withClaim("iss", "https://other.test/")), scope: auth.ScopeRead, expected: errors.ErrInvalidToken},
+		"missing_exp":     {token: sign(t, rsaKey, withoutClaim("exp")), scope: auth.ScopeRead, expected: errors.ErrInvalidToken},
+		"missing_subject": {token: sign(t, rsaKey, withoutClaim("sub")), scope: auth.ScopeRead, expected: errors.ErrInvalidToken},
+		"unknown_key":     {token: sign(t, newRSAKey(t, "rsa-2"), claims("read")), scope: auth.ScopeRead, expected: errors.ErrInvalidToken},
+		"restricted_alg":  {token: sign(t, psKey, claims("read")), scope: auth.ScopeRead, expected: errors.ErrInvalidToken},
+		"malformed":       {token: "not.a.token", scope: auth.ScopeRead, expected: errors.ErrInvalidToken},
+		"tampered":        {token: sign(t, rsaKey, claims("read")) + "x", scope: auth.ScopeRead, expected: errors.ErrInvalidToken},
+		"expired": {
+			token:    sign(t, rsaKey, withClaim("exp", time.Now().Add(-time.Hour).Unix())),
+			scope:    auth.ScopeRead,
+			expected: errors.ErrInvalidToken,
+		},
+		"mismatched_kid": {
+			token:    sign(t, signingKey{kid: "ec-1", method: rsaKey.method, key: rsaKey.key}, claims("read")),
+			scope:    auth.ScopeRead,
+			expected: errors.ErrInvalidToken,
+		},
+		"missing_kid": {
+			token:    sign(t, signingKey{method: rsaKey.method, key: rsaKey.key}, claims("read")),
+			scope:    auth.ScopeRead,
+			expected: errors.ErrInvalidToken,
+		},
+		"symmetric": {
+			token:    sign(t, signingKey{kid: "rsa-1", method: jwt.SigningMethodHS256, key: []byte("s3cret")}, claims("read")),
+			scope:    auth.ScopeRead,
+			expected: errors.ErrInvalidToken,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			subject, err := verifier.Authorize(t.Context(), tc.token, tc.scope)
+			if tc.expected != nil {
+				require.ErrorIs(t, err, tc.expected)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "partner", subject)
+		})
+	}
+}
+
+func TestTokenVerifier_ScopePrefix(t *testing.T) {
+	t.Parallel()
+
+	key := newECKey(t, "ec-1")
+	verifier := newVerifier(t, auth.TokenConfig{ScopeClaim: "permissions", ScopePrefix: "pokedex:"},
+		auth.JWKSConfig{URL: newJWKSServer(t, key).URL})
+
+	c := claims("")
+	c["permissions"] = []string{"pokedex:read", "admin"}
+	token := sign(t, key, c)
+
+	_, err := verifier.Authorize(t.Context(), token, auth.ScopeRead)
+	require.NoError(t, err)
+	_, err = verifier.Authorize(t.Context(), token, auth.ScopeAdmin)
+	require.ErrorIs(t, err, errors.ErrInsufficientScope)
+}
+
+func TestJWKS_Rotation(t *testing.T) {
+	t.Parallel()
+
+	oldKey, newKey := newRSAKey(t, "2025"), newRSAKey(t, "2026")
+	server := newJWKSServer(t, oldKey)
+	verifier := newVerifier(t, auth.TokenConfig{}, auth.JWKSConfig{URL: server.URL, RefreshInterval: time.Hour})
+	require.Equal(t, int64(1), server.requests.Load())
+
+	// The cached key set is used while the key is known
+	_, err := verifier.Authorize(t.Context(), sign(t, oldKey, claims("read")), auth.ScopeRead)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), server.requests.Load())
+
+	// The key set is loaded again when a token is signed with the rotated key
+	server.rotate(t, newKey)
+	_, err = verifier.Authorize(t.Context(), sign(t, newKey, claims("read")), auth.ScopeRead)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), server.requests.Load())
+
+	// The retired key is no longer accepted
+	_, err = verifier.Authorize(t.Context(), sign(t, oldKey, claims("read")), auth.ScopeRead)
+	require.ErrorIs(t, err, errors.ErrInvalidToken)
+}
+
+func TestJWKS_MinRefreshInterval(t *testing.T) {
+	t.Parallel()
+
+	key := newRSAKey(t, "2025")
+	server := newJWKSServer(t, key)
+	verifier := newVerifier(t, auth.TokenConfig{}, auth.JWKSConfig{URL: server.URL, MinRefreshInterval: time.Hour})
+
+	// The tokens signed with unknown keys do not trigger a load before the minimum interval
+	for range 5 {
+		_, err := verifier.Authorize(t.Context(), sign(t, newRSAKey(t, "forged"), claims("read")), auth.ScopeRead)
+		require.ErrorIs(t, err, errors.ErrInvalidToken)
+	}
+	assert.Equal(t, int64(1), server.requests.Load())
+}
+
+func TestJWKS_PeriodicRefresh(t *testing.T) {
+	t.Parallel()
+
+	key := newRSAKey(t, "2025")
+	server := newJWKSServer(t, key)
+	minRefreshInterval := 200 * time.Millisecond
+	verifier := newVerifier(t, auth.TokenConfig{},
+		auth.JWKSConfig{URL: server.URL, RefreshInterval: time.Nanosecond, MinRefreshInterval: minRefreshInterval})
+
+	// The stale key set is refreshed in background, serving the current one meanwhile, at most once per minimum interval
+	time.Sleep(minRefreshInterval)
+	for range 5 {
+		_, err := verifier.Authorize(t.Context(), sign(t, key, claims("read")), auth.ScopeRead)
+		require.NoError(t, err)
+	}
+	assert.Eventually(t, func() bool { return server.requests.Load() == 2 }, time.Second, time.Millisecond)
+	_, err := verifier.Authorize(t.Context(), sign(t, key, claims("read")), auth.ScopeRead)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), server.requests.Load())
+}
+
+func TestJWKS_KeepsKeysOnFailure(t *testing.T) {
+	t.Parallel()
+
+	key := newECKey(t, "ec-1")
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(file, jwksJSON(t, key), 0o600))
+	verifier := newVerifier(t, auth.TokenConfig{}, auth.JWKSConfig{File: file, RefreshInterval: time.Nanosecond})
+
+	// The current keys are kept if the refreshed key set cannot be loaded
+	require.NoError(t, os.WriteFile(file, []byte("not a key set"), 0o600))
+	_, err := verifier.Authorize(t.Context(), sign(t, key, claims("read")), auth.ScopeRead)
+	require.NoError(t, err)
+}
+
+func TestNewJWKS_Invalid(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := auth.NewJWKS(t.Context(), auth.JWKSConfig{})
+	require.ErrorIs(t, err, errors.ErrInvalidConfiguration)
+	_, err = auth.NewJWKS(t.Context(), auth.JWKSConfig{URL: server.URL, File: "jwks.json"})
+	require.ErrorIs(t, err, errors.ErrInvalidConfiguration)
+	_, err = auth.NewJWKS(t.Context(), auth.JWKSConfig{URL: server.URL})
+	require.ErrorIs(t, err, errors.ErrFailedRequest)
+
+	jwks, err := auth.NewJWKS(t.Context(), auth.JWKSConfig{URL: newJWKSServer(t).URL})
+	require.NoError(t, err)
+	_, err = auth.NewTokenVerifier(auth.TokenConfig{Issuer: testIssuer}, jwks)
+	require.ErrorIs(t, err, errors.ErrInvalidConfiguration)
+}
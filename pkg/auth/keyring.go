@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// Usage represents the usage of an API key.
+type Usage struct {
+	Name          string
+	Scopes        []Scope
+	Disabled      bool
+	DailyQuota    int
+	UsedToday     int
+	RejectedToday int
+	TotalRequests int64
+	ResetAt       time.Time
+}
+
+// entry is an API key along with its usage counters.
+type entry struct {
+	key Key
+	// day is the start of the day (UTC) the daily counters refer to.
+	day      time.Time
+	used     int
+	rejected int
+	total    int64
+}
+
+// Keyring holds the API keys, authorizing the requests according to their scopes and daily quotas.
+// The secrets are indexed by their SHA-256 digest, so that the lookup time does not depend on their value.
+type Keyring struct {
+	mu     sync.Mutex
+	byName map[string]*entry
+	bySum  map[[sha256.Size]byte]*entry
+	names  []string
+}
+
+// NewKeyring returns a new Keyring holding the given keys, which must have unique names and values.
+func NewKeyring(keys []Key) (*Keyring, error) {
+	k := &Keyring{
+		byName: make(map[string]*entry, len(keys)),
+		bySum:  make(map[[sha256.Size]byte]*entry, len(keys)),
+	}
+	for i := range keys {
+		key := keys[i]
+		if err := key.validate(); err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256([]byte(key.Secret))
+		if _, found := k.byName[key.Name]; found {
+			return nil, fmt.Errorf("duplicated API key name %q: %w", key.Name, errors.ErrInvalidConfiguration)
+		}
+		if _, found := k.bySum[sum]; found {
+			return nil, fmt.Errorf("API key %q reuses the value of another key: %w", key.Name, errors.ErrInvalidConfiguration)
+		}
+
+		e := &entry{key: key}
+		k.byName[key.Name] = e
+		k.bySum[sum] = e
+		k.names = append(k.names, key.Name)
+	}
+	slices.Sort(k.names)
+	return k, nil
+}
+
+// HasScope returns whether any key is granted the given scope.
+func (k *Keyring) HasScope(scope Scope) bool {
+	for _, e := range k.byName {
+		if e.key.HasScope(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize authorizes a request carrying the given secret on a route requiring the given scope,
+// counting it against the daily quota of the key. It returns the name of the key.
+// The error wraps ErrInvalidAPIKey, ErrInsufficientScope or ErrQuotaExceeded (as QuotaExceededError).
+func (k *Keyring) Authorize(secret string, scope Scope) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e, found := k.bySum[sha256.Sum256([]byte(secret))]
+	if !found {
+		return "", errors.ErrInvalidAPIKey
+	}
+	if e.key.Disabled {
+		return "", fmt.Errorf("API key %q disabled: %w", e.key.Name, errors.ErrInvalidAPIKey)
+	}
+	if !e.key.HasScope(scope) {
+		return "", fmt.Errorf("API key %q without %q scope: %w", e.key.Name, scope, errors.ErrInsufficientScope)
+	}
+
+	now := time.Now().UTC()
+	e.resetIfExpired(now)
+	if e.key.DailyQuota > 0 && e.used >= e.key.DailyQuota {
+		e.rejected++
+		return "", &errors.QuotaExceededError{Key: e.key.Name, Quota: e.key.DailyQuota, RetryAfter: e.day.AddDate(0, 0, 1).Sub(now)}
+	}
+	e.used++
+	e.total++
+	return e.key.Name, nil
+}
+
+// SetDisabled disables or re-enables the key with the given name, until the restart of the server.
+func (k *Keyring) SetDisabled(name string, disabled bool) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e, found := k.byName[name]
+	if !found {
+		return fmt.Errorf("API key %q: %w", name, errors.ErrResourceNotFound)
+	}
+	e.key.Disabled = disabled
+	return nil
+}
+
+// Usage returns the usage of the keys, sorted by name.
+func (k *Keyring) Usage() []Usage {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now().UTC()
+	res := make([]Usage, 0, len(k.names))
+	for _, name := range k.names {
+		e := k.byName[name]
+		e.resetIfExpired(now)
+		res = append(res, Usage{
+			Name:          name,
+			Scopes:        e.key.Scopes,
+			Disabled:      e.key.Disabled,
+			DailyQuota:    e.key.DailyQuota,
+			UsedToday:     e.used,
+			RejectedToday: e.rejected,
+			TotalRequests: e.total,
+			ResetAt:       e.day.AddDate(0, 0, 1),
+		})
+	}
+	return res
+}
+
+// resetIfExpired resets the daily counters if the given time is past their day.
+func (e *entry) resetIfExpired(now time.Time) {
+	day := now.Truncate(24 * time.Hour)
+	if day.After(e.day) {
+		e.day = day
+		e.used = 0
+		e.rejected = 0
+	}
+}
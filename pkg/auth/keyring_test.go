@@ -0,0 +1,103 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/auth"
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+func TestParseKeys(t *testing.T) {
+	t.Parallel()
+
+	keys, err := auth.ParseKeys([]byte(`[{"name": "partner", "key": "s3cret", "scopes": ["read", "translate"], "dailyQuota": 100}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []auth.Key{{Name: "partner", Secret: "s3cret", Scopes: []auth.Scope{auth.ScopeRead, auth.ScopeTranslate}, DailyQuota: 100}}, keys)
+
+	_, err = auth.ParseKeys([]byte(`{"name": "partner"}`))
+	require.ErrorIs(t, err, errors.ErrInvalidConfiguration)
+}
+
+func TestNewKeyring_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string][]auth.Key{
+		"missing_name":   {{Secret: "s3cret"}},
+		"missing_secret": {{Name: "partner"}},
+		"unknown_scope":  {{Name: "partner", Secret: "s3cret", Scopes: []auth.Scope{"write"}}},
+		"negative_quota": {{Name: "partner", Secret: "s3cret", DailyQuota: -1}},
+		"duplicated_name": {
+			{Name: "partner", Secret: "s3cret"},
+			{Name: "partner", Secret: "other"},
+		},
+		"duplicated_secret": {
+			{Name: "partner", Secret: "s3cret"},
+			{Name: "other", Secret: "s3cret"},
+		},
+	}
+
+	for name, keys := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := auth.NewKeyring(keys)
+			require.ErrorIs(t, err, errors.ErrInvalidConfiguration)
+		})
+	}
+}
+
+func TestKeyring_Authorize(t *testing.T) {
+	t.Parallel()
+
+	keyring, err := auth.NewKeyring([]auth.Key{
+		{Name: "partner", Secret: "s3cret", Scopes: []auth.Scope{auth.ScopeRead}, DailyQuota: 2},
+		{Name: "admin", Secret: "adm1n", Scopes: []auth.Scope{auth.ScopeAdmin}},
+	})
+	require.NoError(t, err)
+	assert.True(t, keyring.HasScope(auth.ScopeAdmin))
+	assert.False(t, keyring.HasScope(auth.ScopeTranslate))
+
+	// Unknown keys and missing scopes are rejected
+	_, err = keyring.Authorize("wrong", auth.ScopeRead)
+	require.ErrorIs(t, err, errors.ErrInvalidAPIKey)
+	_, err = keyring.Authorize("s3cret", auth.ScopeTranslate)
+	require.ErrorIs(t, err, errors.ErrInsufficientScope)
+
+	// The requests are counted against the daily quota
+	for range 2 {
+		name, err := keyring.Authorize("s3cret", auth.ScopeRead)
+		require.NoError(t, err)
+		assert.Equal(t, "partner", name)
+	}
+	_, err = keyring.Authorize("s3cret", auth.ScopeRead)
+	require.ErrorIs(t, err, errors.ErrQuotaExceeded)
+
+	var quotaErr *errors.QuotaExceededError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.Positive(t, quotaErr.RetryAfter)
+	assert.LessOrEqual(t, quotaErr.RetryAfter, 24*time.Hour)
+
+	usage := keyring.Usage()
+	require.Len(t, usage, 2)
+	assert.Equal(t, "admin", usage[0].Name)
+	assert.Equal(t, "partner", usage[1].Name)
+	assert.Equal(t, 2, usage[1].UsedToday)
+	assert.Equal(t, 1, usage[1].RejectedToday)
+	assert.Equal(t, int64(2), usage[1].TotalRequests)
+	assert.True(t, usage[1].ResetAt.After(time.Now()))
+
+	// Disabled keys are rejected until enabled again
+	require.NoError(t, keyring.SetDisabled("admin", true))
+	_, err = keyring.Authorize("adm1n", auth.ScopeAdmin)
+	require.ErrorIs(t, err, errors.ErrInvalidAPIKey)
+
+	require.NoError(t, keyring.SetDisabled("admin", false))
+	_, err = keyring.Authorize("adm1n", auth.ScopeAdmin)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, keyring.SetDisabled("unknown", true), errors.ErrResourceNotFound)
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// Scope is a permission granted to an API key.
+type Scope string
+
+// Scopes of the API keys.
+const (
+	// ScopeRead grants the access to the basic Pokemon information.
+	ScopeRead Scope = "read"
+	// ScopeTranslate grants the access to the translated Pokemon information.
+	ScopeTranslate Scope = "translate"
+	// ScopeAdmin grants the access to the administration endpoints.
+	ScopeAdmin Scope = "admin"
+)
+
+// Key is an API key handed out to a client.
+type Key struct {
+	// Name identifies the client, and it is the only reference to the key in logs and usage reports.
+	Name string `json:"name"`
+	// Secret is the value of the key sent by the client.
+	Secret string `json:"key"`
+	// Scopes are the permissions granted to the key.
+	Scopes []Scope `json:"scopes"`
+	// DailyQuota is the maximum number of requests per day (UTC), 0 means unlimited.
+	DailyQuota int `json:"dailyQuota,omitempty"`
+	// Disabled tells whether the key is revoked.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// HasScope returns whether the key is granted the given scope.
+func (k *Key) HasScope(scope Scope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// validate returns an error if the key is not valid.
+func (k *Key) validate() error {
+	switch {
+	case k.Name == "":
+		return fmt.Errorf("API key without name: %w", errors.ErrInvalidConfiguration)
+	case k.Secret == "":
+		return fmt.Errorf("API key %q without value: %w", k.Name, errors.ErrInvalidConfiguration)
+	case k.DailyQuota < 0:
+		return fmt.Errorf("API key %q with negative daily quota: %w", k.Name, errors.ErrInvalidConfiguration)
+	}
+	for _, scope := range k.Scopes {
+		if scope != ScopeRead && scope != ScopeTranslate && scope != ScopeAdmin {
+			return fmt.Errorf("API key %q with unknown scope %q: %w", k.Name, scope, errors.ErrInvalidConfiguration)
+		}
+	}
+	return nil
+}
+
+// ParseKeys parses the API keys from their JSON representation, a list of keys.
+func ParseKeys(data []byte) ([]Key, error) {
+	var keys []Key
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys (%w): %w", err, errors.ErrInvalidConfiguration)
+	}
+	return keys, nil
+}
+
+// LoadKeys loads the API keys from the given JSON file.
+func LoadKeys(path string) ([]Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys file: %w", err)
+	}
+	return ParseKeys(data)
+}
@@ -0,0 +1,253 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+var _ Cache = &BoundedCache{} // check if it implements the Cache interface.
+
+// entry represents a value stored in a BoundedCache.
+type entry struct {
+	key       string
+	value     any
+	size      int64
+	createdAt time.Time
+	expiresAt time.Time // zero value means no expiration
+
+	// Bookkeeping data of the eviction policies
+	element    *list.Element // lru
+	index      int           // lfu
+	frequency  uint64        // lfu
+	lastAccess uint64        // lfu
+}
+
+func (e *entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// BoundedCache represents an in-memory cache bounded by number of entries and estimated memory footprint.
+// When a bound is exceeded, entries are evicted according to the configured policy.
+// Expired entries are removed lazily when accessed, and periodically swept while setting new entries.
+type BoundedCache struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+	policy  evictionPolicy
+
+	defaultExpiration time.Duration
+	cleanupInterval   time.Duration
+	maxEntries        int
+	maxBytes          int64
+
+	bytes       int64
+	lastCleanup time.Time
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// NewBoundedCache returns a new BoundedCache according to the given configuration.
+func NewBoundedCache(cfg *Config) (*BoundedCache, error) {
+	policy, err := newEvictionPolicy(cfg.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxEntries < 0 || cfg.MaxBytes < 0 {
+		return nil, fmt.Errorf("cache bounds must not be negative: %w", errors.ErrInvalidConfiguration)
+	}
+
+	return &BoundedCache{
+		entries:           make(map[string]*entry),
+		policy:            policy,
+		defaultExpiration: cfg.DefaultExpiration,
+		cleanupInterval:   cfg.CleanupInterval,
+		maxEntries:        cfg.MaxEntries,
+		maxBytes:          cfg.MaxBytes,
+		lastCleanup:       time.Now(),
+	}, nil
+}
+
+// Get returns the value stored for the key, if present and not expired.
+func (c *BoundedCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, found := c.entries[key]
+	if !found {
+		c.misses++
+		return nil, false
+	}
+	if e.expired(time.Now()) {
+		c.removeEntry(e)
+		c.misses++
+		return nil, false
+	}
+
+	c.policy.touch(e)
+	c.hits++
+	return e.value, true
+}
+
+// Set stores the value for the key with the given time to live.
+// Values larger than the maximum memory footprint of the cache are not stored.
+func (c *BoundedCache) Set(key string, value any, ttl time.Duration) {
+	size := estimateSize(key, value)
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, found := c.entries[key]; found {
+		c.removeEntry(old)
+	}
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return
+	}
+
+	c.cleanupExpired(now)
+	c.makeRoom(size)
+
+	e := &entry{
+		key:       key,
+		value:     value,
+		size:      size,
+		createdAt: now,
+		expiresAt: c.expiration(now, ttl),
+	}
+	c.entries[key] = e
+	c.bytes += size
+	c.policy.add(e)
+}
+
+// Delete removes the key from the cache.
+func (c *BoundedCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, found := c.entries[key]; found {
+		c.removeEntry(e)
+	}
+}
+
+// Stats returns a snapshot of the cache statistics.
+func (c *BoundedCache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   len(c.entries),
+		Bytes:     c.bytes,
+	}
+}
+
+// Keys returns the sorted keys of the entries not expired and starting with the given prefix.
+func (c *BoundedCache) Keys(prefix string) []string {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := []string{}
+	for key, e := range c.entries {
+		if strings.HasPrefix(key, prefix) && !e.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Peek returns the entry stored for the key, if present and not expired, without counting it as an access.
+func (c *BoundedCache) Peek(key string) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, found := c.entries[key]
+	if !found || e.expired(time.Now()) {
+		return Entry{}, false
+	}
+	return Entry{
+		Key:       e.key,
+		Value:     e.value,
+		Size:      e.size,
+		CreatedAt: e.createdAt,
+		ExpiresAt: e.expiresAt,
+	}, true
+}
+
+// DeletePrefix removes all the keys starting with the given prefix, and returns the number of removed entries.
+func (c *BoundedCache) DeletePrefix(prefix string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deleted := 0
+	for key, e := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.removeEntry(e)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (c *BoundedCache) expiration(now time.Time, ttl time.Duration) time.Time {
+	if ttl == DefaultExpiration {
+		ttl = c.defaultExpiration
+	}
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+// makeRoom evicts entries until a new entry of the given size fits within the bounds of the cache.
+// Evicting before inserting prevents the LFU policy from selecting the new entry as victim.
+// It must be called with the lock held.
+func (c *BoundedCache) makeRoom(size int64) {
+	for (c.maxEntries > 0 && len(c.entries) >= c.maxEntries) || (c.maxBytes > 0 && c.bytes+size > c.maxBytes) {
+		victim := c.policy.victim()
+		if victim == nil {
+			return
+		}
+		c.removeEntry(victim)
+		c.evictions++
+	}
+}
+
+// cleanupExpired removes all the expired entries, if the cleanup interval elapsed since the last sweep.
+// It must be called with the lock held.
+func (c *BoundedCache) cleanupExpired(now time.Time) {
+	if c.cleanupInterval <= 0 || now.Sub(c.lastCleanup) < c.cleanupInterval {
+		return
+	}
+	c.lastCleanup = now
+
+	for _, e := range c.entries {
+		if e.expired(now) {
+			c.removeEntry(e)
+		}
+	}
+}
+
+// removeEntry removes the entry from the cache. It must be called with the lock held.
+func (c *BoundedCache) removeEntry(e *entry) {
+	delete(c.entries, e.key)
+	c.bytes -= e.size
+	c.policy.remove(e)
+}
+
+// Ping checks whether the cache backend is reachable. The in-memory backend is always reachable.
+func (c *BoundedCache) Ping(context.Context) error {
+	return nil
+}
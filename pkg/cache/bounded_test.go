@@ -0,0 +1,111 @@
+package cache_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/cache"
+)
+
+func TestBoundedCache_LRUEviction(t *testing.T) {
+	t.Parallel()
+
+	c, err := cache.NewBoundedCache(&cache.Config{MaxEntries: 2, Policy: cache.PolicyLRU})
+	require.NoError(t, err)
+
+	c.Set("a", "1", cache.NoExpiration)
+	c.Set("b", "2", cache.NoExpiration)
+
+	// Access "a" so that "b" becomes the least recently used entry
+	_, found := c.Get("a")
+	require.True(t, found)
+
+	c.Set("c", "3", cache.NoExpiration)
+
+	_, found = c.Get("b")
+	assert.False(t, found, "least recently used entry should have been evicted")
+	_, found = c.Get("a")
+	assert.True(t, found)
+	_, found = c.Get("c")
+	assert.True(t, found)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, uint64(3), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+}
+
+func TestBoundedCache_LFUEviction(t *testing.T) {
+	t.Parallel()
+
+	c, err := cache.NewBoundedCache(&cache.Config{MaxEntries: 2, Policy: cache.PolicyLFU})
+	require.NoError(t, err)
+
+	c.Set("a", "1", cache.NoExpiration)
+	c.Set("b", "2", cache.NoExpiration)
+
+	// Access "a" multiple times and "b" once, then "b" again as the most recent access
+	for range 3 {
+		_, _ = c.Get("a")
+	}
+	_, _ = c.Get("b")
+
+	c.Set("c", "3", cache.NoExpiration)
+
+	_, found := c.Get("b")
+	assert.False(t, found, "least frequently used entry should have been evicted")
+	_, found = c.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, uint64(1), c.Stats().Evictions)
+}
+
+func TestBoundedCache_MaxBytes(t *testing.T) {
+	t.Parallel()
+
+	value := strings.Repeat("x", 100)
+	c, err := cache.NewBoundedCache(&cache.Config{MaxBytes: 500})
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		c.Set(key, value, cache.NoExpiration)
+	}
+
+	stats := c.Stats()
+	assert.LessOrEqual(t, stats.Bytes, int64(500))
+	assert.Positive(t, stats.Evictions)
+
+	// A value larger than the whole cache is never stored
+	c.Set("huge", strings.Repeat("x", 1000), cache.NoExpiration)
+	_, found := c.Get("huge")
+	assert.False(t, found)
+}
+
+func TestBoundedCache_Expiration(t *testing.T) {
+	t.Parallel()
+
+	c, err := cache.NewBoundedCache(&cache.Config{MaxEntries: 10, DefaultExpiration: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	c.Set("a", "1", cache.DefaultExpiration)
+	c.Set("b", "2", cache.NoExpiration)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, found := c.Get("a")
+	assert.False(t, found, "entry should have expired")
+	_, found = c.Get("b")
+	assert.True(t, found)
+	assert.Equal(t, 1, c.Stats().Entries)
+}
+
+func TestNew_InvalidPolicy(t *testing.T) {
+	t.Parallel()
+
+	_, err := cache.New(&cache.Config{MaxEntries: 10, Policy: "fifo"})
+	require.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// Policy represents the eviction policy of a bounded cache.
+type Policy string
+
+const (
+	// PolicyLRU evicts the least recently used entry first.
+	PolicyLRU Policy = "lru"
+	// PolicyLFU evicts the least frequently used entry first.
+	PolicyLFU Policy = "lfu"
+)
+
+// Config contains the configuration of a cache.
+type Config struct {
+	// DefaultExpiration is the time to live of entries stored with DefaultExpiration.
+	DefaultExpiration time.Duration
+	// CleanupInterval is the interval between two sweeps of the expired entries.
+	CleanupInterval time.Duration
+	// MaxEntries is the maximum number of entries (0 means unlimited).
+	MaxEntries int
+	// MaxBytes is the maximum estimated memory footprint of the entries (0 means unlimited).
+	MaxBytes int64
+	// Policy is the eviction policy used when a bound is exceeded.
+	Policy Policy
+}
+
+// New returns a new cache according to the given configuration.
+// A bounded cache is returned if at least one bound is set, an unbounded one otherwise.
+func New(cfg *Config) (Cache, error) { //nolint:ireturn // the backend is selected at runtime
+	if cfg.MaxEntries <= 0 && cfg.MaxBytes <= 0 {
+		return NewMemoryCache(cfg.DefaultExpiration, cfg.CleanupInterval), nil
+	}
+	return NewBoundedCache(cfg)
+}
+
+func newEvictionPolicy(policy Policy) (evictionPolicy, error) {
+	switch policy {
+	case PolicyLRU, "":
+		return newLRUPolicy(), nil
+	case PolicyLFU:
+		return newLFUPolicy(), nil
+	default:
+		return nil, fmt.Errorf("invalid eviction policy %q: %w", policy, errors.ErrInvalidConfiguration)
+	}
+}
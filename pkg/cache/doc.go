@@ -0,0 +1,4 @@
+// Package cache provides the in-memory caches used by the cached clients.
+// Two backends are available: an unbounded cache relying on time-based expiration only,
+// and a size-bounded cache evicting entries according to an LRU or LFU policy.
+package cache
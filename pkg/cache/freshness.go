@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type freshnessKey struct{}
+
+// Freshness records the earliest expiration of the cache entries serving a request, so that the clients of the server
+// do not cache its response for longer than the entries it was built from.
+type Freshness struct {
+	mu        sync.Mutex
+	expiresAt time.Time
+}
+
+// WithFreshness returns a copy of the context carrying a new Freshness, recording the cache entries served with it.
+func WithFreshness(ctx context.Context) (context.Context, *Freshness) {
+	freshness := &Freshness{}
+	return context.WithValue(ctx, freshnessKey{}, freshness), freshness
+}
+
+// ObserveExpiration records that a cache entry expiring at the given time was served with the context.
+// It does nothing if the context carries no Freshness, or if the entry never expires.
+func ObserveExpiration(ctx context.Context, expiresAt time.Time) {
+	freshness, ok := ctx.Value(freshnessKey{}).(*Freshness)
+	if !ok || expiresAt.IsZero() {
+		return
+	}
+
+	freshness.mu.Lock()
+	defer freshness.mu.Unlock()
+	if freshness.expiresAt.IsZero() || expiresAt.Before(freshness.expiresAt) {
+		freshness.expiresAt = expiresAt
+	}
+}
+
+// TTL returns the remaining time to live of the earliest expiring cache entry observed, and false if none was observed.
+func (f *Freshness) TTL() (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.expiresAt.IsZero() {
+		return 0, false
+	}
+	return max(time.Until(f.expiresAt), 0), true
+}
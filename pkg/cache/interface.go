@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultExpiration makes Set use the default expiration configured for the cache.
+	DefaultExpiration time.Duration = 0
+	// NoExpiration makes Set store an entry that never expires.
+	NoExpiration time.Duration = -1
+)
+
+// Cache is an interface that defines the methods of a key-value cache.
+type Cache interface {
+	// Get returns the value stored for the key, if present and not expired.
+	Get(key string) (any, bool)
+	// Set stores the value for the key with the given time to live.
+	Set(key string, value any, ttl time.Duration)
+	// Delete removes the key from the cache.
+	Delete(key string)
+	// Stats returns a snapshot of the cache statistics.
+	Stats() Stats
+
+	// Keys returns the sorted keys of the entries not expired and starting with the given prefix.
+	Keys(prefix string) []string
+	// Peek returns the entry stored for the key, if present and not expired, without counting it as an access.
+	Peek(key string) (Entry, bool)
+	// DeletePrefix removes all the keys starting with the given prefix, and returns the number of removed entries.
+	DeletePrefix(prefix string) int
+
+	// Ping checks whether the cache backend is reachable.
+	Ping(ctx context.Context) error
+}
+
+// Entry represents a snapshot of an entry stored in a cache.
+type Entry struct {
+	Key       string
+	Value     any
+	Size      int64
+	CreatedAt time.Time
+	ExpiresAt time.Time // zero value means no expiration
+}
+
+// Stats contains the statistics of a cache.
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+	Bytes     int64  `json:"bytes"`
+}
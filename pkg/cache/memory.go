@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+var _ Cache = &MemoryCache{} // check if it implements the Cache interface.
+
+// MemoryCache represents an unbounded in-memory cache, whose entries are removed only when they expire.
+type MemoryCache struct {
+	cache  *gocache.Cache
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// memoryItem wraps the values stored in a MemoryCache to keep track of their creation time.
+type memoryItem struct {
+	value     any
+	createdAt time.Time
+}
+
+// NewMemoryCache returns a new unbounded MemoryCache.
+func NewMemoryCache(defaultExpiration, cleanupInterval time.Duration) *MemoryCache {
+	return &MemoryCache{
+		cache: gocache.New(defaultExpiration, cleanupInterval),
+	}
+}
+
+// Get returns the value stored for the key, if present and not expired.
+func (c *MemoryCache) Get(key string) (any, bool) {
+	data, found := c.cache.Get(key)
+	item, ok := data.(memoryItem)
+	if !found || !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return item.value, true
+}
+
+// Set stores the value for the key with the given time to live.
+func (c *MemoryCache) Set(key string, value any, ttl time.Duration) {
+	c.cache.Set(key, memoryItem{value: value, createdAt: time.Now()}, ttl)
+}
+
+// Delete removes the key from the cache.
+func (c *MemoryCache) Delete(key string) {
+	c.cache.Delete(key)
+}
+
+// Stats returns a snapshot of the cache statistics.
+func (c *MemoryCache) Stats() Stats {
+	return Stats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: c.cache.ItemCount(),
+	}
+}
+
+// Keys returns the sorted keys of the entries not expired and starting with the given prefix.
+func (c *MemoryCache) Keys(prefix string) []string {
+	keys := []string{}
+	for key := range c.cache.Items() { // Items already skips the expired entries
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Peek returns the entry stored for the key, if present and not expired, without counting it as an access.
+func (c *MemoryCache) Peek(key string) (Entry, bool) {
+	data, expiration, found := c.cache.GetWithExpiration(key)
+	item, ok := data.(memoryItem)
+	if !found || !ok {
+		return Entry{}, false
+	}
+	return Entry{
+		Key:       key,
+		Value:     item.value,
+		Size:      estimateSize(key, item.value),
+		CreatedAt: item.createdAt,
+		ExpiresAt: expiration,
+	}, true
+}
+
+// DeletePrefix removes all the keys starting with the given prefix, and returns the number of removed entries.
+func (c *MemoryCache) DeletePrefix(prefix string) int {
+	keys := c.Keys(prefix)
+	for _, key := range keys {
+		c.cache.Delete(key)
+	}
+	return len(keys)
+}
+
+// Ping checks whether the cache backend is reachable. The in-memory backend is always reachable.
+func (c *MemoryCache) Ping(context.Context) error {
+	return nil
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"container/heap"
+	"container/list"
+)
+
+// evictionPolicy keeps track of the entries accesses and selects the next entry to evict.
+type evictionPolicy interface {
+	add(e *entry)
+	touch(e *entry)
+	remove(e *entry)
+	victim() *entry
+}
+
+// lruPolicy evicts the least recently used entry, keeping the entries in a list ordered by access time.
+type lruPolicy struct {
+	entries *list.List
+}
+
+func newLRUPolicy() *lruPolicy {
+	return &lruPolicy{entries: list.New()}
+}
+
+func (p *lruPolicy) add(e *entry) {
+	e.element = p.entries.PushFront(e)
+}
+
+func (p *lruPolicy) touch(e *entry) {
+	p.entries.MoveToFront(e.element)
+}
+
+func (p *lruPolicy) remove(e *entry) {
+	p.entries.Remove(e.element)
+	e.element = nil
+}
+
+func (p *lruPolicy) victim() *entry {
+	back := p.entries.Back()
+	if back == nil {
+		return nil
+	}
+	e, _ := back.Value.(*entry)
+	return e
+}
+
+// lfuPolicy evicts the least frequently used entry, keeping the entries in a min-heap ordered by access count.
+// Ties are broken by evicting the least recently used entry.
+type lfuPolicy struct {
+	entries lfuHeap
+	clock   uint64
+}
+
+func newLFUPolicy() *lfuPolicy {
+	return &lfuPolicy{}
+}
+
+func (p *lfuPolicy) add(e *entry) {
+	p.clock++
+	e.frequency = 1
+	e.lastAccess = p.clock
+	heap.Push(&p.entries, e)
+}
+
+func (p *lfuPolicy) touch(e *entry) {
+	p.clock++
+	e.frequency++
+	e.lastAccess = p.clock
+	heap.Fix(&p.entries, e.index)
+}
+
+func (p *lfuPolicy) remove(e *entry) {
+	heap.Remove(&p.entries, e.index)
+}
+
+func (p *lfuPolicy) victim() *entry {
+	if len(p.entries) == 0 {
+		return nil
+	}
+	return p.entries[0]
+}
+
+// lfuHeap implements heap.Interface for the entries tracked by the lfuPolicy.
+type lfuHeap []*entry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].frequency != h[j].frequency {
+		return h[i].frequency < h[j].frequency
+	}
+	return h[i].lastAccess < h[j].lastAccess
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x any) {
+	e, _ := x.(*entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *lfuHeap) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
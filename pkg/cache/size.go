@@ -0,0 +1,30 @@
+package cache
+
+import "encoding/json"
+
+// entryOverhead is the estimated memory footprint of the bookkeeping data of an entry.
+const entryOverhead = 64
+
+// Sizer is implemented by values able to report their own memory footprint, in bytes.
+type Sizer interface {
+	Size() int
+}
+
+// estimateSize returns the estimated memory footprint of an entry.
+// Values not implementing Sizer are estimated from the length of their JSON encoding.
+func estimateSize(key string, value any) int64 {
+	size := entryOverhead + len(key)
+	switch v := value.(type) {
+	case string:
+		size += len(v)
+	case []byte:
+		size += len(v)
+	case Sizer:
+		size += v.Size()
+	default:
+		if data, err := json.Marshal(v); err == nil {
+			size += len(data)
+		}
+	}
+	return int64(size)
+}
@@ -0,0 +1,350 @@
+package catalog
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/health"
+	"github.com/fra98/pokedex/pkg/suggest"
+)
+
+var (
+	_ health.Checker  = &Catalog{} // check if it implements the Checker interface.
+	_ health.Detailer = &Catalog{} // check if it implements the Detailer interface.
+	_ suggest.Source  = &Catalog{} // check if it implements the Source interface.
+)
+
+const (
+	// maxPokemonID is the upper bound of the IDs of the default Pokemon, whose ID matches the one of their species.
+	// The PokeAPI gives the alternative forms (e.g., mega evolutions) IDs starting from 10001.
+	maxPokemonID = 10000
+	// maxFailedFraction is the fraction of the listed species that can fail to be retrieved without failing the build,
+	// so that an upstream outage during the build (e.g., the circuit breaker opening) does not replace the catalog with a partial one.
+	maxFailedFraction = 0.01
+	// retryInterval is the interval after which a failed build is retried, unless the refresh interval is shorter.
+	retryInterval = 10 * time.Minute
+)
+
+// Entry is a Pokemon species of the catalog.
+type Entry struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Generation  int      `json:"generation"`
+	Habitat     string   `json:"habitat"`
+	IsLegendary bool     `json:"isLegendary"`
+	Types       []string `json:"types"`
+}
+
+// Config contains the configuration of the catalog.
+type Config struct {
+	// Concurrency is the maximum number of concurrent requests to the PokeAPI while building the catalog.
+	Concurrency int
+	// Interval is the minimum interval between two consecutive requests to the PokeAPI while building the catalog.
+	Interval time.Duration
+	// PageSize is the number of species retrieved per page of the species list.
+	PageSize int
+	// RefreshInterval is the interval between two builds of the catalog. The catalog is built once if zero.
+	RefreshInterval time.Duration
+	// File is the path of the local snapshot of the catalog, loaded at startup and saved after each build. Disabled if empty.
+	File string
+}
+
+// snapshot is an immutable build of the catalog, with the entries sorted by ID and by name.
+type snapshot struct {
+	BuiltAt time.Time `json:"builtAt"`
+	Entries []Entry   `json:"entries"`
+
+	byName []Entry
+	names  []string
+}
+
+func newSnapshot(builtAt time.Time, entries []Entry) *snapshot {
+	slices.SortFunc(entries, func(a, b Entry) int { return cmp.Compare(a.ID, b.ID) })
+	byName := slices.Clone(entries)
+	slices.SortFunc(byName, func(a, b Entry) int { return cmp.Compare(a.Name, b.Name) })
+	names := make([]string, 0, len(byName))
+	for _, e := range byName {
+		names = append(names, e.Name)
+	}
+	return &snapshot{BuiltAt: builtAt, Entries: entries, byName: byName, names: names}
+}
+
+// Catalog indexes all the Pokemon species listed by the PokeAPI, along with their generation, habitat and types.
+// The species are retrieved through the given client, usually a cached one, so that the build also warms the cache.
+type Catalog struct {
+	client pokeapi.Client
+	config Config
+
+	current atomic.Pointer[snapshot]
+}
+
+// NewCatalog returns a new Catalog retrieving the species from the given client. The catalog is empty until Run is called.
+func NewCatalog(client pokeapi.Client, cfg *Config) *Catalog {
+	config := *cfg
+	config.Concurrency = max(config.Concurrency, 1)
+	if config.PageSize <= 0 {
+		config.PageSize = 200
+	}
+	return &Catalog{client: client, config: config}
+}
+
+// Run loads the local snapshot, if any, then builds the catalog and rebuilds it every refresh interval,
+// until the context is canceled. A fresh snapshot is served without being rebuilt until it is due for a refresh.
+// Failed builds are logged and retried after the retry interval, while the previous build keeps being served.
+func (c *Catalog) Run(ctx context.Context) {
+	var next time.Duration
+	if s := c.load(ctx); s != nil && c.config.RefreshInterval > 0 {
+		next = max(time.Until(s.BuiltAt.Add(c.config.RefreshInterval)), 0)
+	}
+
+	for {
+		select {
+		case <-time.After(next):
+		case <-ctx.Done():
+			return
+		}
+
+		if err := c.Build(ctx); err != nil {
+			slog.ErrorContext(ctx, "Catalog build failed", "error", err)
+			next = retryInterval
+			if c.config.RefreshInterval > 0 {
+				next = min(next, c.config.RefreshInterval)
+			}
+			continue
+		}
+		if c.config.RefreshInterval <= 0 {
+			return
+		}
+		next = c.config.RefreshInterval
+	}
+}
+
+// Build pages through the species list and retrieves every species and type, with bounded concurrency and rate pacing,
+// replacing the served catalog once completed. Failures to retrieve a single species are logged and the species is skipped,
+// but the build fails, keeping the served catalog, if more than the maximum fraction of the species failed.
+// The species not found are skipped without counting as failures.
+func (c *Catalog) Build(ctx context.Context) error {
+	start := time.Now()
+
+	entries, err := c.fetchSpecies(ctx)
+	if err != nil {
+		return err
+	}
+	types, err := c.fetchTypes(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		entries[i].Types = types[entries[i].ID]
+	}
+
+	s := newSnapshot(start, entries)
+	c.current.Store(s)
+	slog.InfoContext(ctx, "Catalog built", "duration", time.Since(start).Round(time.Millisecond), "species", len(entries))
+
+	c.save(ctx, s)
+	return nil
+}
+
+// Check returns nil if the catalog is built (or loaded from the local snapshot), an error otherwise.
+func (c *Catalog) Check(_ context.Context) error {
+	if c.current.Load() == nil {
+		return fmt.Errorf("catalog build in progress: %w", errors.ErrNotReady)
+	}
+	return nil
+}
+
+// SpeciesNames returns the names of all the species of the catalog, sorted, or an error if the catalog is not built yet.
+// The returned slice must not be modified.
+func (c *Catalog) SpeciesNames(_ context.Context) ([]string, error) {
+	s := c.current.Load()
+	if s == nil {
+		return nil, fmt.Errorf("catalog build in progress: %w", errors.ErrNotReady)
+	}
+	return s.names, nil
+}
+
+// Details returns the size and the build time of the catalog, reported by the readiness checks.
+func (c *Catalog) Details() map[string]any {
+	s := c.current.Load()
+	if s == nil {
+		return map[string]any{"species": 0}
+	}
+	return map[string]any{"species": len(s.Entries), "builtAt": s.BuiltAt}
+}
+
+// fetchSpecies pages through the species list, retrieving the species by the configured number of workers.
+// It returns an error if more than the maximum fraction of the species failed to be retrieved.
+func (c *Catalog) fetchSpecies(ctx context.Context) ([]Entry, error) {
+	names := make(chan string)
+	var (
+		mu      sync.Mutex
+		entries []Entry
+		listed  atomic.Int64
+		failed  atomic.Int64
+		wg      sync.WaitGroup
+	)
+	for range c.config.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				listed.Add(1)
+				species, err := c.client.GetPokemonSpecies(ctx, name)
+				if err != nil {
+					if !stderrors.Is(err, errors.ErrResourceNotFound) {
+						failed.Add(1)
+					}
+					slog.WarnContext(ctx, "Catalog skipping species", "species", name, "error", err)
+					continue
+				}
+				mu.Lock()
+				entries = append(entries, Entry{
+					ID:          species.ID,
+					Name:        species.Name,
+					Generation:  species.Generation.ID(),
+					Habitat:     species.Habitat.Name,
+					IsLegendary: species.IsLegendary,
+				})
+				mu.Unlock()
+			}
+		}()
+	}
+
+	err := c.produce(ctx, names)
+	close(names)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	if float64(failed.Load()) > maxFailedFraction*float64(listed.Load()) {
+		return nil, fmt.Errorf("failed to retrieve %d of %d species: %w", failed.Load(), listed.Load(), errors.ErrIncompleteCatalog)
+	}
+	return entries, nil
+}
+
+// produce pages through the species list, sending the species names to the channel paced by the configured interval.
+func (c *Catalog) produce(ctx context.Context, names chan<- string) error {
+	var ticker *time.Ticker
+	if c.config.Interval > 0 {
+		ticker = time.NewTicker(c.config.Interval)
+		defer ticker.Stop()
+	}
+
+	for offset := 0; ; {
+		page, err := c.client.ListPokemonSpecies(ctx, offset, c.config.PageSize)
+		if err != nil {
+			return fmt.Errorf("failed to list species at offset %d: %w", offset, err)
+		}
+
+		for i := range page.Results {
+			if ticker != nil {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return fmt.Errorf("catalog build interrupted: %w", ctx.Err())
+				}
+			}
+			select {
+			case names <- page.Results[i].Name:
+			case <-ctx.Done():
+				return fmt.Errorf("catalog build interrupted: %w", ctx.Err())
+			}
+		}
+
+		offset += len(page.Results)
+		if page.Next == nil || len(page.Results) == 0 {
+			return nil
+		}
+	}
+}
+
+// fetchTypes returns the types of each species, indexed by species ID and sorted by slot.
+// The types of a species are the ones of its default Pokemon, sharing the same ID.
+func (c *Catalog) fetchTypes(ctx context.Context) (map[int][]string, error) {
+	list, err := c.client.ListTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list types: %w", err)
+	}
+
+	slots := make(map[int]map[int]string)
+	for _, ref := range list.Results {
+		t, err := c.client.GetType(ctx, ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get type %q: %w", ref.Name, err)
+		}
+		for _, p := range t.Pokemon {
+			id := p.Pokemon.ID()
+			if id <= 0 || id > maxPokemonID {
+				continue
+			}
+			if slots[id] == nil {
+				slots[id] = make(map[int]string)
+			}
+			slots[id][p.Slot] = t.Name
+		}
+	}
+
+	types := make(map[int][]string, len(slots))
+	for id, bySlot := range slots {
+		for _, slot := range slices.Sorted(maps.Keys(bySlot)) {
+			types[id] = append(types[id], bySlot[slot])
+		}
+	}
+	return types, nil
+}
+
+// load serves the local snapshot, if configured and readable, returning it.
+func (c *Catalog) load(ctx context.Context) *snapshot {
+	if c.config.File == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.config.File)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.WarnContext(ctx, "Failed to read catalog snapshot", "file", c.config.File, "error", err)
+		}
+		return nil
+	}
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		slog.WarnContext(ctx, "Failed to decode catalog snapshot", "file", c.config.File, "error", err)
+		return nil
+	}
+
+	loaded := newSnapshot(s.BuiltAt, s.Entries)
+	c.current.Store(loaded)
+	slog.InfoContext(ctx, "Catalog loaded from snapshot", "file", c.config.File, "species", len(loaded.Entries), "builtAt", loaded.BuiltAt)
+	return loaded
+}
+
+// save writes the snapshot to the local file, if configured, replacing the previous one atomically.
+func (c *Catalog) save(ctx context.Context, s *snapshot) {
+	if c.config.File == "" {
+		return
+	}
+
+	data, err := json.Marshal(s)
+	if err == nil {
+		tmp := c.config.File + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o600); err == nil {
+			err = os.Rename(tmp, c.config.File)
+		}
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to save catalog snapshot", "file", c.config.File, "error", err)
+	}
+}
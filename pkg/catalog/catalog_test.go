@@ -0,0 +1,326 @@
+package catalog_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/catalog"
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// testSpecies are the species served by the test PokeAPI, along with their types by slot.
+var testSpecies = []struct {
+	id         int
+	name       string
+	generation int
+	habitat    string
+	legendary  bool
+	types      []string
+}{
+	{1, "bulbasaur", 1, "grassland", false, []string{"grass", "poison"}},
+	{7, "squirtle", 1, "waters-edge", false, []string{"water"}},
+	{54, "psyduck", 1, "waters-edge", false, []string{"water"}},
+	{131, "lapras", 1, "sea", false, []string{"water", "ice"}},
+	{144, "articuno", 1, "rare", true, []string{"ice", "flying"}},
+	{150, "mewtwo", 1, "rare", true, []string{"psychic"}},
+	{249, "lugia", 2, "rare", true, []string{"psychic", "flying"}},
+	{382, "kyogre", 3, "sea", true, []string{"water"}},
+}
+
+// newPokeServer is a helper function to setup a test server for the PokeAPI serving the test species and their types.
+// The species list also includes "missingno", which does not exist. It returns the test server and a counter of the requests.
+func newPokeServer(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+
+		var res any
+		switch path := strings.TrimSuffix(r.URL.Path, "/"); {
+		case path == "/pokemon-species":
+			res = speciesList(r)
+		case strings.HasPrefix(path, "/pokemon-species/"):
+			res = species(strings.TrimPrefix(path, "/pokemon-species/"))
+		case path == "/type":
+			res = typeList()
+		case strings.HasPrefix(path, "/type/"):
+			res = pokemonType(strings.TrimPrefix(path, "/type/"))
+		}
+		if res == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	return server, &requests
+}
+
+func speciesList(r *http.Request) *pokeapi.NamedAPIResourceList {
+	names := []string{"missingno"}
+	for _, s := range testSpecies {
+		names = append(names, s.name)
+	}
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	list := &pokeapi.NamedAPIResourceList{Count: len(names)}
+	for _, name := range names[min(offset, len(names)):min(offset+limit, len(names))] {
+		list.Results = append(list.Results, pokeapi.NamedAPIResource{Name: name})
+	}
+	if offset+limit < len(names) {
+		next := "next"
+		list.Next = &next
+	}
+	return list
+}
+
+// species returns the test species with the given name, or nil if it does not exist.
+func species(name string) any {
+	for _, s := range testSpecies {
+		if s.name == name {
+			return &pokeapi.PokemonSpecies{
+				ID:          s.id,
+				Name:        s.name,
+				IsLegendary: s.legendary,
+				Habitat:     pokeapi.Habitat{Name: s.habitat},
+				Generation:  pokeapi.NamedAPIResource{URL: "https://pokeapi.co/api/v2/generation/" + strconv.Itoa(s.generation) + "/"},
+			}
+		}
+	}
+	return nil
+}
+
+func typeList() *pokeapi.NamedAPIResourceList {
+	list := &pokeapi.NamedAPIResourceList{}
+	for _, name := range []string{"grass", "poison", "water", "ice", "flying", "psychic", "fire"} {
+		list.Results = append(list.Results, pokeapi.NamedAPIResource{Name: name})
+	}
+	return list
+}
+
+func pokemonType(name string) *pokeapi.Type {
+	t := &pokeapi.Type{Name: name, Pokemon: []pokeapi.TypePokemon{}}
+	for _, s := range testSpecies {
+		for i, typ := range s.types {
+			if typ == name {
+				t.Pokemon = append(t.Pokemon, pokeapi.TypePokemon{Slot: i + 1, Pokemon: pokeapi.NamedAPIResource{
+					Name: s.name, URL: "https://pokeapi.co/api/v2/pokemon/" + strconv.Itoa(s.id) + "/",
+				}})
+			}
+		}
+	}
+	if name == "water" {
+		// Alternative forms are not species, and they are ignored
+		t.Pokemon = append(t.Pokemon, pokeapi.TypePokemon{Slot: 1, Pokemon: pokeapi.NamedAPIResource{
+			Name: "kyogre-primal", URL: "https://pokeapi.co/api/v2/pokemon/10077/",
+		}})
+	}
+	return t
+}
+
+// newCatalog is a helper function to setup a catalog built from the test PokeAPI.
+func newCatalog(t *testing.T, cfg *catalog.Config) *catalog.Catalog {
+	t.Helper()
+
+	pokeServer, _ := newPokeServer(t)
+	t.Cleanup(pokeServer.Close)
+
+	c := catalog.NewCatalog(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), cfg)
+	require.NoError(t, c.Build(t.Context()))
+	return c
+}
+
+func names(entries []catalog.Entry) []string {
+	res := make([]string, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e.Name)
+	}
+	return res
+}
+
+func TestCatalog_List(t *testing.T) {
+	t.Parallel()
+
+	c := newCatalog(t, &catalog.Config{Concurrency: 3, PageSize: 4})
+	legendary, notLegendary := true, false
+
+	testCases := map[string]struct {
+		query    catalog.Query
+		expected []string
+	}{
+		"all":        {query: catalog.Query{}, expected: []string{"bulbasaur", "squirtle", "psyduck", "lapras", "articuno", "mewtwo", "lugia", "kyogre"}},
+		"by_name":    {query: catalog.Query{Sort: catalog.SortName, Limit: 3}, expected: []string{"articuno", "bulbasaur", "kyogre"}},
+		"habitat":    {query: catalog.Query{Habitat: "sea"}, expected: []string{"lapras", "kyogre"}},
+		"legendary":  {query: catalog.Query{Legendary: &legendary}, expected: []string{"articuno", "mewtwo", "lugia", "kyogre"}},
+		"common":     {query: catalog.Query{Legendary: &notLegendary, Generation: 1}, expected: []string{"bulbasaur", "squirtle", "psyduck", "lapras"}},
+		"generation": {query: catalog.Query{Generation: 2}, expected: []string{"lugia"}},
+		"type":       {query: catalog.Query{Type: "water"}, expected: []string{"squirtle", "psyduck", "lapras", "kyogre"}},
+		"secondary":  {query: catalog.Query{Type: "flying", Legendary: &legendary}, expected: []string{"articuno", "lugia"}},
+		"combined":   {query: catalog.Query{Habitat: "sea", Legendary: &legendary, Generation: 3, Type: "water"}, expected: []string{"kyogre"}},
+		"none":       {query: catalog.Query{Type: "fire"}, expected: []string{}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			page, err := c.List(&tc.query)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, names(page.Entries))
+		})
+	}
+}
+
+func TestCatalog_Entries(t *testing.T) {
+	t.Parallel()
+
+	c := newCatalog(t, &catalog.Config{})
+
+	page, err := c.List(&catalog.Query{Habitat: "sea", Legendary: new(bool)})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, catalog.Entry{
+		ID:          131,
+		Name:        "lapras",
+		Generation:  1,
+		Habitat:     "sea",
+		IsLegendary: false,
+		Types:       []string{"water", "ice"},
+	}, page.Entries[0])
+}
+
+func TestCatalog_SpeciesNames(t *testing.T) {
+	t.Parallel()
+
+	// The names of the species are provided to the suggestions, without the species not found
+	names, err := newCatalog(t, &catalog.Config{}).SpeciesNames(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"articuno", "bulbasaur", "kyogre", "lapras", "lugia", "mewtwo", "psyduck", "squirtle"}, names)
+}
+
+func TestCatalog_Pagination(t *testing.T) {
+	t.Parallel()
+
+	c := newCatalog(t, &catalog.Config{})
+
+	for sort, expected := range map[catalog.Sort][]string{
+		catalog.SortDex:  {"squirtle", "psyduck", "lapras", "kyogre"},
+		catalog.SortName: {"kyogre", "lapras", "psyduck", "squirtle"},
+	} {
+		query := catalog.Query{Type: "water", Sort: sort, Limit: 3}
+		page, err := c.List(&query)
+		require.NoError(t, err)
+		assert.Equal(t, expected[:3], names(page.Entries))
+		assert.Equal(t, 4, page.Total)
+		require.NotEmpty(t, page.Next)
+
+		query.Cursor = page.Next
+		page, err = c.List(&query)
+		require.NoError(t, err)
+		assert.Equal(t, expected[3:], names(page.Entries))
+		assert.Equal(t, 4, page.Total)
+		assert.Empty(t, page.Next)
+	}
+
+	// The cursors are bound to the query they were issued for
+	page, err := c.List(&catalog.Query{Type: "water", Limit: 1})
+	require.NoError(t, err)
+	_, err = c.List(&catalog.Query{Type: "ice", Limit: 1, Cursor: page.Next})
+	require.ErrorIs(t, err, errors.ErrInvalidCursor)
+	_, err = c.List(&catalog.Query{Type: "water", Sort: catalog.SortName, Limit: 1, Cursor: page.Next})
+	require.ErrorIs(t, err, errors.ErrInvalidCursor)
+	_, err = c.List(&catalog.Query{Cursor: "not-a-cursor!"})
+	require.ErrorIs(t, err, errors.ErrInvalidCursor)
+}
+
+func TestCatalog_NotReady(t *testing.T) {
+	t.Parallel()
+
+	pokeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer pokeServer.Close()
+
+	c := catalog.NewCatalog(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), &catalog.Config{})
+	require.Error(t, c.Build(t.Context()))
+
+	require.ErrorIs(t, c.Check(t.Context()), errors.ErrNotReady)
+	_, err := c.List(&catalog.Query{})
+	require.ErrorIs(t, err, errors.ErrNotReady)
+	_, err = c.SpeciesNames(t.Context())
+	require.ErrorIs(t, err, errors.ErrNotReady)
+}
+
+func TestCatalog_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "catalog.json")
+	built := newCatalog(t, &catalog.Config{File: file})
+	expected, err := built.List(&catalog.Query{})
+	require.NoError(t, err)
+
+	// A fresh snapshot is served without contacting the PokeAPI
+	pokeServer, requests := newPokeServer(t)
+	defer pokeServer.Close()
+	c := catalog.NewCatalog(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), &catalog.Config{File: file, RefreshInterval: time.Hour})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go c.Run(ctx)
+
+	require.Eventually(t, func() bool { return c.Check(t.Context()) == nil }, time.Second, 10*time.Millisecond)
+	page, err := c.List(&catalog.Query{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, page)
+	assert.Zero(t, requests.Load())
+}
+
+func TestCatalog_IncompleteBuild(t *testing.T) {
+	t.Parallel()
+
+	// The species requests fail while failing is set, as when the upstream circuit breaker opens
+	pokeServer, _ := newPokeServer(t)
+	t.Cleanup(pokeServer.Close)
+	var failing atomic.Bool
+	flakyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() && strings.HasPrefix(strings.TrimSuffix(r.URL.Path, "/"), "/pokemon-species/") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		pokeServer.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(flakyServer.Close)
+
+	file := filepath.Join(t.TempDir(), "catalog.json")
+	c := catalog.NewCatalog(pokeapi.NewPokeAPIClient(&flakyServer.URL, nil), &catalog.Config{File: file})
+	require.NoError(t, c.Build(t.Context()))
+	expected, err := c.List(&catalog.Query{})
+	require.NoError(t, err)
+	saved, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	// The incomplete build is rejected, and the previous catalog keeps being served and saved
+	failing.Store(true)
+	require.ErrorIs(t, c.Build(t.Context()), errors.ErrIncompleteCatalog)
+	page, err := c.List(&catalog.Query{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, page)
+	current, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, saved, current)
+}
@@ -0,0 +1,2 @@
+// Package catalog provides the catalog of the Pokemon species, an index built from the PokeAPI to browse and filter them.
+package catalog
@@ -0,0 +1,159 @@
+package catalog
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// Sort is the order of the listed species.
+type Sort string
+
+// Orders of the listed species.
+const (
+	SortDex  Sort = "dex"
+	SortName Sort = "name"
+)
+
+// DefaultLimit is the number of species of a page, if the query sets no limit.
+const DefaultLimit = 20
+
+// Query selects a page of the species matching the filters, in the requested order.
+// The zero values of the filters match all the species.
+type Query struct {
+	Habitat    string
+	Legendary  *bool
+	Generation int
+	Type       string
+	// Sort is the order of the species, by National Pokedex number if empty.
+	Sort Sort
+	// Limit is the maximum number of species of the page, DefaultLimit if not positive.
+	Limit int
+	// Cursor is the opaque cursor of the page, returned by the previous one. The first page is returned if empty.
+	Cursor string
+}
+
+// Page is a page of the species matching a query.
+type Page struct {
+	Entries []Entry
+	// Total is the number of species matching the filters, across all the pages.
+	Total int
+	// Next is the cursor of the next page, empty if this is the last one.
+	Next string
+}
+
+// cursor is the position of a page, encoded as base64 JSON. It holds the sort key of the last species of the previous page,
+// so that the pages stay consistent when the catalog is rebuilt, along with the fingerprint of the query it belongs to.
+type cursor struct {
+	Sort   Sort   `json:"s"`
+	ID     int    `json:"i,omitempty"`
+	Name   string `json:"n,omitempty"`
+	Filter uint64 `json:"f"`
+}
+
+// List returns the page of the species matching the query.
+// It fails with ErrNotReady if the catalog is not built yet, and with ErrInvalidCursor if the cursor does not belong to the query.
+func (c *Catalog) List(q *Query) (*Page, error) {
+	s := c.current.Load()
+	if s == nil {
+		return nil, fmt.Errorf("catalog build in progress: %w", errors.ErrNotReady)
+	}
+
+	order, sorted := SortDex, s.Entries
+	if q.Sort == SortName {
+		order, sorted = SortName, s.byName
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	filter := q.fingerprint()
+	start := 0
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if after.Sort != order || after.Filter != filter {
+			return nil, fmt.Errorf("cursor of another query: %w", errors.ErrInvalidCursor)
+		}
+		start = after.position(sorted)
+	}
+
+	page := &Page{Entries: []Entry{}}
+	for i, e := range sorted {
+		if !q.matches(&e) {
+			continue
+		}
+		page.Total++
+		if i < start {
+			continue
+		}
+		if len(page.Entries) == limit {
+			if page.Next == "" {
+				last := page.Entries[limit-1]
+				page.Next = cursor{Sort: order, ID: last.ID, Name: last.Name, Filter: filter}.encode()
+			}
+			continue
+		}
+		page.Entries = append(page.Entries, e)
+	}
+	return page, nil
+}
+
+// matches returns whether the species matches the filters of the query.
+func (q *Query) matches(e *Entry) bool {
+	return (q.Habitat == "" || e.Habitat == q.Habitat) &&
+		(q.Legendary == nil || e.IsLegendary == *q.Legendary) &&
+		(q.Generation == 0 || e.Generation == q.Generation) &&
+		(q.Type == "" || slices.Contains(e.Types, q.Type))
+}
+
+// fingerprint returns the hash of the filters of the query, binding the cursors to the query they were issued for.
+func (q *Query) fingerprint() uint64 {
+	legendary := ""
+	if q.Legendary != nil {
+		legendary = strconv.FormatBool(*q.Legendary)
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.Join([]string{q.Habitat, legendary, strconv.Itoa(q.Generation), q.Type}, "\x00")))
+	return h.Sum64()
+}
+
+func (cur cursor) encode() string {
+	data, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(encoded string) (cursor, error) {
+	var cur cursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cur, fmt.Errorf("malformed cursor: %w", errors.ErrInvalidCursor)
+	}
+	if err := json.Unmarshal(data, &cur); err != nil || (cur.Sort != SortDex && cur.Sort != SortName) {
+		return cur, fmt.Errorf("malformed cursor: %w", errors.ErrInvalidCursor)
+	}
+	return cur, nil
+}
+
+// position returns the index of the first species following the cursor in the sorted species.
+func (cur cursor) position(sorted []Entry) int {
+	i, _ := slices.BinarySearchFunc(sorted, cur, func(e Entry, cur cursor) int {
+		if cur.Sort == SortName {
+			return strings.Compare(e.Name, cur.Name)
+		}
+		return e.ID - cur.ID
+	})
+	if i < len(sorted) && sorted[i].ID == cur.ID {
+		i++
+	}
+	return i
+}
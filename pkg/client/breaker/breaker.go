@@ -0,0 +1,238 @@
+package breaker
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/health"
+)
+
+var (
+	_ health.Checker  = &Breaker{} // check if it implements the Checker interface.
+	_ health.Detailer = &Breaker{} // check if it implements the Detailer interface.
+)
+
+// State represents the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed lets all the requests through, counting the consecutive failures.
+	StateClosed State = iota
+	// StateOpen rejects all the requests until the open timeout elapses.
+	StateOpen
+	// StateHalfOpen lets a limited number of probe requests through, to check whether the upstream recovered.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Config contains the configuration of a circuit breaker.
+type Config struct {
+	// FailureThreshold is the number of consecutive failures opening the circuit.
+	FailureThreshold int
+	// OpenTimeout is the time the circuit stays open before letting probe requests through.
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests is the number of successful probe requests closing the circuit.
+	HalfOpenMaxRequests int
+}
+
+// Stats contains the statistics of a circuit breaker.
+type Stats struct {
+	State     State
+	Failures  uint64
+	Rejected  uint64
+	Openings  uint64
+	Successes uint64
+}
+
+// Breaker is a circuit breaker with closed, open and half-open states.
+type Breaker struct {
+	name   string
+	config Config
+
+	mu                sync.Mutex
+	state             State
+	consecutiveFails  int
+	openedAt          time.Time
+	halfOpenInFlight  int
+	halfOpenSuccesses int
+	stats             Stats
+	// generation is incremented at each state change, so that the outcomes of the requests admitted before are ignored.
+	generation uint64
+}
+
+// New returns a new closed Breaker for the named upstream.
+func New(name string, cfg *Config) *Breaker {
+	config := *cfg
+	config.FailureThreshold = max(config.FailureThreshold, 1)
+	config.HalfOpenMaxRequests = max(config.HalfOpenMaxRequests, 1)
+	return &Breaker{name: name, config: config}
+}
+
+// Name returns the name of the upstream protected by the breaker.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh(time.Now())
+	return b.state
+}
+
+// Stats returns a snapshot of the breaker statistics.
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh(time.Now())
+	stats := b.stats
+	stats.State = b.state
+	return stats
+}
+
+// Details returns the state and the statistics of the breaker, reported by the readiness checks.
+func (b *Breaker) Details() map[string]any {
+	stats := b.Stats()
+	return map[string]any{
+		"state":     stats.State.String(),
+		"failures":  stats.Failures,
+		"rejected":  stats.Rejected,
+		"openings":  stats.Openings,
+		"successes": stats.Successes,
+	}
+}
+
+// Check returns an error if the breaker is open.
+func (b *Breaker) Check(_ context.Context) error {
+	if state := b.State(); state == StateOpen {
+		return fmt.Errorf("%s circuit breaker is %s: %w", b.name, state, errors.ErrCircuitOpen)
+	}
+	return nil
+}
+
+// Do calls fn if the breaker allows it, recording its outcome. If the breaker is open,
+// fn is not called and a *errors.CircuitOpenError is returned.
+func Do[T any](b *Breaker, fn func() (T, error)) (T, error) {
+	var zero T
+	generation, err := b.allow()
+	if err != nil {
+		return zero, err
+	}
+
+	res, err := fn()
+	b.record(generation, err)
+	return res, err
+}
+
+// allow returns the current generation if a request can be sent to the upstream, reserving a probe slot if the breaker
+// is half-open, and an error otherwise.
+func (b *Breaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.refresh(now)
+
+	switch b.state {
+	case StateClosed:
+		return b.generation, nil
+	case StateHalfOpen:
+		if b.halfOpenInFlight+b.halfOpenSuccesses < b.config.HalfOpenMaxRequests {
+			b.halfOpenInFlight++
+			return b.generation, nil
+		}
+	case StateOpen:
+	}
+
+	b.stats.Rejected++
+	return 0, &errors.CircuitOpenError{
+		Upstream:   b.name,
+		RetryAfter: max(b.openedAt.Add(b.config.OpenTimeout).Sub(now), time.Second),
+	}
+}
+
+// record updates the breaker according to the outcome of a request.
+// Requests canceled by the caller are not counted, and not found resources are successes
+// since the upstream answered correctly. Requests admitted in an earlier generation are ignored,
+// e.g., a request admitted while closed and completing once half-open is not counted as a probe.
+func (b *Breaker) record(generation uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+	if b.state == StateHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+
+	switch {
+	case err == nil, stderrors.Is(err, errors.ErrResourceNotFound):
+		b.onSuccess()
+	case stderrors.Is(err, context.Canceled):
+		// The outcome says nothing about the upstream health
+	default:
+		b.onFailure(time.Now())
+	}
+}
+
+func (b *Breaker) onSuccess() {
+	b.stats.Successes++
+	b.consecutiveFails = 0
+	if b.state == StateHalfOpen {
+		b.halfOpenSuccesses++
+		if b.halfOpenSuccesses >= b.config.HalfOpenMaxRequests {
+			b.setState(StateClosed, time.Time{})
+		}
+	}
+}
+
+func (b *Breaker) onFailure(now time.Time) {
+	b.stats.Failures++
+	b.consecutiveFails++
+	switch b.state {
+	case StateHalfOpen:
+		b.setState(StateOpen, now)
+	case StateClosed:
+		if b.consecutiveFails >= b.config.FailureThreshold {
+			b.setState(StateOpen, now)
+		}
+	case StateOpen:
+	}
+}
+
+// refresh moves the breaker from open to half-open once the open timeout elapsed.
+func (b *Breaker) refresh(now time.Time) {
+	if b.state == StateOpen && now.Sub(b.openedAt) >= b.config.OpenTimeout {
+		b.setState(StateHalfOpen, time.Time{})
+	}
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	b.state = state
+	b.generation++
+	b.consecutiveFails = 0
+	b.halfOpenInFlight = 0
+	b.halfOpenSuccesses = 0
+	if state == StateOpen {
+		b.openedAt = now
+		b.stats.Openings++
+	}
+}
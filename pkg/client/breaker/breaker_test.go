@@ -0,0 +1,126 @@
+package breaker_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/client/breaker"
+	apperrors "github.com/fra98/pokedex/pkg/errors"
+)
+
+var errUpstream = fmt.Errorf("upstream down: %w", apperrors.ErrFailedRequest)
+
+// call is a helper function calling fn through the breaker, returning whether fn was actually called.
+func call(b *breaker.Breaker, err error) (called bool, res error) {
+	_, res = breaker.Do(b, func() (struct{}, error) {
+		called = true
+		return struct{}{}, err
+	})
+	return called, res
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+
+	b := breaker.New("pokeapi", &breaker.Config{FailureThreshold: 2, OpenTimeout: time.Hour})
+
+	// A success resets the consecutive failures
+	_, _ = call(b, errUpstream)
+	_, _ = call(b, nil)
+	_, _ = call(b, errUpstream)
+	assert.Equal(t, breaker.StateClosed, b.State())
+
+	_, _ = call(b, errUpstream)
+	assert.Equal(t, breaker.StateOpen, b.State())
+	require.Error(t, b.Check(t.Context()))
+
+	// While open, requests fail fast without reaching the upstream
+	called, err := call(b, nil)
+	assert.False(t, called)
+	require.ErrorIs(t, err, apperrors.ErrCircuitOpen)
+
+	var circuitOpenErr *apperrors.CircuitOpenError
+	require.ErrorAs(t, err, &circuitOpenErr)
+	assert.Equal(t, "pokeapi", circuitOpenErr.Upstream)
+	assert.Greater(t, circuitOpenErr.RetryAfter, 59*time.Minute)
+
+	stats := b.Stats()
+	assert.Equal(t, uint64(1), stats.Openings)
+	assert.Equal(t, uint64(1), stats.Rejected)
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	t.Parallel()
+
+	openTimeout := 20 * time.Millisecond
+	b := breaker.New("pokeapi", &breaker.Config{FailureThreshold: 1, OpenTimeout: openTimeout, HalfOpenMaxRequests: 1})
+
+	_, _ = call(b, errUpstream)
+	require.Equal(t, breaker.StateOpen, b.State())
+
+	// A failing probe opens the circuit again
+	time.Sleep(2 * openTimeout)
+	assert.Equal(t, breaker.StateHalfOpen, b.State())
+	called, _ := call(b, errUpstream)
+	assert.True(t, called)
+	assert.Equal(t, breaker.StateOpen, b.State())
+
+	// A successful probe closes the circuit
+	time.Sleep(2 * openTimeout)
+	called, err := call(b, nil)
+	assert.True(t, called)
+	require.NoError(t, err)
+	assert.Equal(t, breaker.StateClosed, b.State())
+}
+
+func TestBreaker_IgnoredOutcomes(t *testing.T) {
+	t.Parallel()
+
+	b := breaker.New("pokeapi", &breaker.Config{FailureThreshold: 1, OpenTimeout: time.Hour})
+
+	// Not found resources and requests canceled by the caller do not open the circuit
+	_, _ = call(b, fmt.Errorf("unknown species: %w", apperrors.ErrResourceNotFound))
+	_, _ = call(b, fmt.Errorf("client gone: %w", context.Canceled))
+	assert.Equal(t, breaker.StateClosed, b.State())
+
+	_, err := call(b, errUpstream)
+	require.ErrorIs(t, err, apperrors.ErrFailedRequest)
+	assert.Equal(t, breaker.StateOpen, b.State())
+}
+
+func TestBreaker_StaleOutcome(t *testing.T) {
+	t.Parallel()
+
+	openTimeout := 20 * time.Millisecond
+	b := breaker.New("pokeapi", &breaker.Config{FailureThreshold: 1, OpenTimeout: openTimeout, HalfOpenMaxRequests: 1})
+
+	// A slow request is admitted while closed, and completes once the breaker turned half-open
+	admitted, release := make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = breaker.Do(b, func() (struct{}, error) {
+			close(admitted)
+			<-release
+			return struct{}{}, nil
+		})
+	}()
+	<-admitted
+	_, _ = call(b, errUpstream)
+	time.Sleep(2 * openTimeout)
+	require.Equal(t, breaker.StateHalfOpen, b.State())
+	close(release)
+	<-done
+
+	// Its success is not counted as a probe, so the breaker stays half-open and the probe slot is still available
+	assert.Equal(t, breaker.StateHalfOpen, b.State())
+	called, err := call(b, nil)
+	assert.True(t, called)
+	require.NoError(t, err)
+	assert.Equal(t, breaker.StateClosed, b.State())
+}
@@ -0,0 +1,2 @@
+// Package breaker provides a circuit breaker to fail fast when an upstream dependency is unavailable.
+package breaker
@@ -0,0 +1,145 @@
+package bulkhead
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/health"
+)
+
+var (
+	_ health.Checker  = &Bulkhead{} // check if it implements the Checker interface.
+	_ health.Detailer = &Bulkhead{} // check if it implements the Detailer interface.
+)
+
+// Config contains the configuration of a bulkhead.
+type Config struct {
+	// MaxConcurrent is the maximum number of concurrent requests.
+	MaxConcurrent int
+	// MaxQueue is the maximum number of requests waiting for a free slot (zero rejects immediately when full).
+	MaxQueue int
+	// QueueTimeout is the maximum time a request waits for a free slot (zero means until the request is canceled).
+	QueueTimeout time.Duration
+}
+
+// Stats contains the statistics of a bulkhead.
+type Stats struct {
+	// Active is the number of requests in flight.
+	Active int
+	// Queued is the number of requests waiting for a free slot.
+	Queued int
+	// RejectedQueueFull is the number of requests rejected since the wait queue was full.
+	RejectedQueueFull uint64
+	// RejectedTimeout is the number of requests rejected since the wait timed out.
+	RejectedTimeout uint64
+}
+
+// Bulkhead limits the number of concurrent requests to an upstream, queueing a bounded number of the exceeding ones.
+type Bulkhead struct {
+	name   string
+	config Config
+
+	slots             chan struct{}
+	queued            atomic.Int64
+	rejectedQueueFull atomic.Uint64
+	rejectedTimeout   atomic.Uint64
+}
+
+// New returns a new Bulkhead for the named upstream.
+func New(name string, cfg *Config) *Bulkhead {
+	config := *cfg
+	config.MaxConcurrent = max(config.MaxConcurrent, 1)
+	config.MaxQueue = max(config.MaxQueue, 0)
+	return &Bulkhead{
+		name:   name,
+		config: config,
+		slots:  make(chan struct{}, config.MaxConcurrent),
+	}
+}
+
+// Name returns the name of the upstream protected by the bulkhead.
+func (b *Bulkhead) Name() string {
+	return b.name
+}
+
+// Stats returns the statistics of the bulkhead.
+func (b *Bulkhead) Stats() Stats {
+	return Stats{
+		Active:            len(b.slots),
+		Queued:            int(b.queued.Load()),
+		RejectedQueueFull: b.rejectedQueueFull.Load(),
+		RejectedTimeout:   b.rejectedTimeout.Load(),
+	}
+}
+
+// Details returns the statistics of the bulkhead, reported by the readiness checks.
+func (b *Bulkhead) Details() map[string]any {
+	stats := b.Stats()
+	return map[string]any{
+		"active":            stats.Active,
+		"queued":            stats.Queued,
+		"rejectedQueueFull": stats.RejectedQueueFull,
+		"rejectedTimeout":   stats.RejectedTimeout,
+	}
+}
+
+// Check returns an error if the bulkhead is saturated, i.e., all the slots are taken and the wait queue is full.
+func (b *Bulkhead) Check(context.Context) error {
+	if len(b.slots) == cap(b.slots) && int(b.queued.Load()) >= b.config.MaxQueue {
+		return fmt.Errorf("%s: %w", b.name, errors.ErrBulkheadFull)
+	}
+	return nil
+}
+
+// acquire takes a slot, waiting in the queue if none is free. It returns an error wrapping ErrBulkheadFull
+// if the queue is full or the wait times out.
+func (b *Bulkhead) acquire(ctx context.Context) error {
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if b.queued.Add(1) > int64(b.config.MaxQueue) {
+		b.queued.Add(-1)
+		b.rejectedQueueFull.Add(1)
+		return fmt.Errorf("%s: wait queue full: %w", b.name, errors.ErrBulkheadFull)
+	}
+	defer b.queued.Add(-1)
+
+	var timeout <-chan time.Time
+	if b.config.QueueTimeout > 0 {
+		timer := time.NewTimer(b.config.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-timeout:
+		b.rejectedTimeout.Add(1)
+		return fmt.Errorf("%s: timed out waiting in queue: %w", b.name, errors.ErrBulkheadFull)
+	case <-ctx.Done():
+		return fmt.Errorf("%s: canceled waiting in queue: %w", b.name, ctx.Err())
+	}
+}
+
+func (b *Bulkhead) release() {
+	<-b.slots
+}
+
+// Do calls fn once a slot of the bulkhead is available, returning an error wrapping ErrBulkheadFull
+// without calling it if the request is rejected.
+func Do[T any](ctx context.Context, b *Bulkhead, fn func() (T, error)) (T, error) {
+	if err := b.acquire(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+	defer b.release()
+
+	return fn()
+}
@@ -0,0 +1,105 @@
+package bulkhead_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/client/bulkhead"
+	apperrors "github.com/fra98/pokedex/pkg/errors"
+)
+
+// occupy is a helper function taking a slot of the bulkhead until the returned function is called.
+func occupy(t *testing.T, b *bulkhead.Bulkhead) func() {
+	t.Helper()
+
+	acquired := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		_, err := bulkhead.Do(context.Background(), b, func() (struct{}, error) {
+			close(acquired)
+			<-done
+			return struct{}{}, nil
+		})
+		assert.NoError(t, err)
+	}()
+	<-acquired
+	return func() { close(done) }
+}
+
+func call(ctx context.Context, b *bulkhead.Bulkhead) (called bool, err error) {
+	_, err = bulkhead.Do(ctx, b, func() (struct{}, error) {
+		called = true
+		return struct{}{}, nil
+	})
+	return called, err
+}
+
+func TestBulkhead_QueueFull(t *testing.T) {
+	t.Parallel()
+
+	b := bulkhead.New("translator", &bulkhead.Config{MaxConcurrent: 1})
+	release := occupy(t, b)
+	defer release()
+
+	// The bulkhead is saturated
+	require.ErrorIs(t, b.Check(t.Context()), apperrors.ErrBulkheadFull)
+
+	called, err := call(t.Context(), b)
+	assert.False(t, called)
+	require.ErrorIs(t, err, apperrors.ErrBulkheadFull)
+
+	stats := b.Stats()
+	assert.Equal(t, 1, stats.Active)
+	assert.Equal(t, uint64(1), stats.RejectedQueueFull)
+}
+
+func TestBulkhead_QueueTimeout(t *testing.T) {
+	t.Parallel()
+
+	b := bulkhead.New("translator", &bulkhead.Config{MaxConcurrent: 1, MaxQueue: 1, QueueTimeout: 20 * time.Millisecond})
+	release := occupy(t, b)
+	defer release()
+
+	called, err := call(t.Context(), b)
+	assert.False(t, called)
+	require.ErrorIs(t, err, apperrors.ErrBulkheadFull)
+	assert.Equal(t, uint64(1), b.Stats().RejectedTimeout)
+}
+
+func TestBulkhead_QueuedRequestProceeds(t *testing.T) {
+	t.Parallel()
+
+	b := bulkhead.New("translator", &bulkhead.Config{MaxConcurrent: 1, MaxQueue: 1, QueueTimeout: time.Second})
+	release := occupy(t, b)
+
+	// Free the slot while the request is waiting in the queue
+	time.AfterFunc(20*time.Millisecond, release)
+
+	called, err := call(t.Context(), b)
+	require.NoError(t, err)
+	assert.True(t, called)
+
+	stats := b.Stats()
+	assert.Equal(t, 0, stats.Queued)
+	assert.Equal(t, uint64(0), stats.RejectedQueueFull+stats.RejectedTimeout)
+}
+
+func TestBulkhead_Canceled(t *testing.T) {
+	t.Parallel()
+
+	b := bulkhead.New("translator", &bulkhead.Config{MaxConcurrent: 1, MaxQueue: 1})
+	release := occupy(t, b)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	called, err := call(ctx, b)
+	assert.False(t, called)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotErrorIs(t, err, apperrors.ErrBulkheadFull)
+}
@@ -0,0 +1,3 @@
+// Package bulkhead provides a bulkhead limiting the concurrent requests to an upstream dependency,
+// so that a slow upstream cannot starve the whole server.
+package bulkhead
@@ -0,0 +1,3 @@
+// Package hedge provides hedged requests, reducing the tail latency of an upstream dependency
+// by racing a second identical request against the slow ones.
+package hedge
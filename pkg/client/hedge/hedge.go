@@ -0,0 +1,192 @@
+package hedge
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultPercentile is the latency percentile used as hedging delay when not configured.
+	DefaultPercentile = 0.95
+	// DefaultWindowSize is the number of latency samples used to compute the hedging delay when not configured.
+	DefaultWindowSize = 1000
+	// minSamples is the number of latency samples required before using the percentile as hedging delay.
+	minSamples = 20
+	// recomputeSamples is the number of latency samples observed between two computations of the percentile,
+	// which sorts the whole window and so is not done on every request.
+	recomputeSamples = 50
+)
+
+// Config contains the configuration of the hedged requests.
+type Config struct {
+	// Percentile is the latency percentile (in the (0, 1] range) after which a hedged request is fired.
+	Percentile float64
+	// MinDelay and MaxDelay bound the hedging delay. MaxDelay is also used until enough latencies are observed.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// MaxHedgesPerSecond caps the hedged requests, to avoid amplifying the load on the upstream (zero disables hedging).
+	MaxHedgesPerSecond float64
+	// WindowSize is the number of most recent latencies used to compute the percentile.
+	WindowSize int
+}
+
+// Stats contains the statistics of the hedged requests.
+type Stats struct {
+	// Delay is the current hedging delay.
+	Delay time.Duration
+	// Hedges is the number of hedged requests fired.
+	Hedges uint64
+	// Won is the number of hedged requests completed before the original ones.
+	Won uint64
+	// Throttled is the number of hedged requests not fired because of the rate cap.
+	Throttled uint64
+}
+
+// Hedger fires hedged requests when the original ones take longer than a percentile of the observed latencies.
+type Hedger struct {
+	config  Config
+	limiter *rate.Limiter
+
+	mu        sync.Mutex
+	latencies []time.Duration
+	next      int
+	// pending is the number of latencies observed since the last computation of the delay, computed is false until the first one.
+	pending  int
+	computed bool
+	delay    atomic.Int64
+
+	hedges    atomic.Uint64
+	won       atomic.Uint64
+	throttled atomic.Uint64
+}
+
+// New returns a new Hedger.
+func New(cfg *Config) *Hedger {
+	config := *cfg
+	if config.Percentile <= 0 || config.Percentile > 1 {
+		config.Percentile = DefaultPercentile
+	}
+	if config.WindowSize <= 0 {
+		config.WindowSize = DefaultWindowSize
+	}
+	config.MaxDelay = max(config.MaxDelay, config.MinDelay)
+
+	burst := int(math.Ceil(max(config.MaxHedgesPerSecond, 0)))
+	h := &Hedger{
+		config:    config,
+		limiter:   rate.NewLimiter(rate.Limit(max(config.MaxHedgesPerSecond, 0)), burst),
+		latencies: make([]time.Duration, 0, config.WindowSize),
+	}
+	h.delay.Store(int64(config.MaxDelay))
+	return h
+}
+
+// Delay returns the current hedging delay, i.e., the configured percentile of the observed latencies.
+// It is recomputed every recomputeSamples observed latencies, once enough of them are observed.
+func (h *Hedger) Delay() time.Duration {
+	return time.Duration(h.delay.Load())
+}
+
+// Stats returns the statistics of the hedged requests.
+func (h *Hedger) Stats() Stats {
+	return Stats{
+		Delay:     h.Delay(),
+		Hedges:    h.hedges.Load(),
+		Won:       h.won.Load(),
+		Throttled: h.throttled.Load(),
+	}
+}
+
+// observe records the latency of a completed request in the sliding window, recomputing the delay if due.
+// The window is sorted outside of the lock, so that the other requests are not blocked meanwhile.
+func (h *Hedger) observe(latency time.Duration) {
+	h.mu.Lock()
+	if len(h.latencies) < h.config.WindowSize {
+		h.latencies = append(h.latencies, latency)
+	} else {
+		h.latencies[h.next] = latency
+		h.next = (h.next + 1) % h.config.WindowSize
+	}
+	h.pending++
+
+	var sorted []time.Duration
+	if len(h.latencies) >= minSamples && (!h.computed || h.pending >= recomputeSamples) {
+		sorted = slices.Clone(h.latencies)
+		h.pending, h.computed = 0, true
+	}
+	h.mu.Unlock()
+
+	if sorted != nil {
+		slices.Sort(sorted)
+		idx := int(math.Ceil(h.config.Percentile*float64(len(sorted)))) - 1
+		h.delay.Store(int64(min(max(sorted[max(idx, 0)], h.config.MinDelay), h.config.MaxDelay)))
+	}
+}
+
+type result[T any] struct {
+	value   T
+	err     error
+	hedged  bool
+	latency time.Duration
+}
+
+// Do calls fn and, if it has not completed within the hedging delay, calls it again concurrently, returning the
+// first successful result. The request still in flight is canceled through its context. If the first completed
+// request fails, the result of the other one (if fired) is awaited.
+func Do[T any](ctx context.Context, h *Hedger, fn func(ctx context.Context) (T, error)) (T, error) {
+	// Canceling the context on return cancels the losing request
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The latencies are timed from the start of the call, so that a winning hedged request, launched after the delay,
+	// does not pull the percentile down to the latency of the upstream alone
+	start := time.Now()
+
+	// Buffered, so that the losing request never blocks
+	results := make(chan result[T], 2)
+	launch := func(hedged bool) {
+		go func() {
+			value, err := fn(ctx)
+			results <- result[T]{value: value, err: err, hedged: hedged, latency: time.Since(start)}
+		}()
+	}
+
+	launch(false)
+	inFlight := 1
+
+	timer := time.NewTimer(h.Delay())
+	defer timer.Stop()
+	timerC := timer.C
+
+	for {
+		select {
+		case res := <-results:
+			inFlight--
+			if res.err == nil {
+				h.observe(res.latency)
+				if res.hedged {
+					h.won.Add(1)
+				}
+				return res.value, nil
+			}
+			if inFlight == 0 {
+				return res.value, res.err
+			}
+		case <-timerC:
+			timerC = nil
+			if !h.limiter.Allow() {
+				h.throttled.Add(1)
+				continue
+			}
+			h.hedges.Add(1)
+			launch(true)
+			inFlight++
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package hedge_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/client/hedge"
+)
+
+func TestDo_HedgeWins(t *testing.T) {
+	t.Parallel()
+
+	h := hedge.New(&hedge.Config{MinDelay: 10 * time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxHedgesPerSecond: 100})
+
+	var calls atomic.Int64
+	loserCanceled := make(chan struct{})
+	res, err := hedge.Do(t.Context(), h, func(ctx context.Context) (string, error) {
+		if calls.Add(1) == 1 {
+			// The original request hangs until canceled
+			<-ctx.Done()
+			close(loserCanceled)
+			return "", ctx.Err()
+		}
+		return "hedged", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hedged", res)
+
+	select {
+	case <-loserCanceled:
+	case <-time.After(time.Second):
+		require.Fail(t, "the losing request was not canceled")
+	}
+
+	stats := h.Stats()
+	assert.Equal(t, uint64(1), stats.Hedges)
+	assert.Equal(t, uint64(1), stats.Won)
+}
+
+func TestDo_FastRequestsNotHedged(t *testing.T) {
+	t.Parallel()
+
+	h := hedge.New(&hedge.Config{MinDelay: 20 * time.Millisecond, MaxDelay: time.Second, MaxHedgesPerSecond: 100})
+
+	var calls atomic.Int64
+	for range 50 {
+		_, err := hedge.Do(t.Context(), h, func(context.Context) (struct{}, error) {
+			calls.Add(1)
+			return struct{}{}, nil
+		})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(50), calls.Load())
+	stats := h.Stats()
+	assert.Equal(t, uint64(0), stats.Hedges)
+	// The observed latencies are below the minimum delay, which bounds the percentile
+	assert.Equal(t, 20*time.Millisecond, stats.Delay)
+}
+
+func TestDo_HedgesCapped(t *testing.T) {
+	t.Parallel()
+
+	h := hedge.New(&hedge.Config{MinDelay: 5 * time.Millisecond, MaxDelay: 5 * time.Millisecond, MaxHedgesPerSecond: 1})
+
+	slow := func(context.Context) (struct{}, error) {
+		time.Sleep(30 * time.Millisecond)
+		return struct{}{}, nil
+	}
+
+	// The first slow request is hedged, the second exceeds the cap
+	_, err := hedge.Do(t.Context(), h, slow)
+	require.NoError(t, err)
+	_, err = hedge.Do(t.Context(), h, slow)
+	require.NoError(t, err)
+
+	stats := h.Stats()
+	assert.Equal(t, uint64(1), stats.Hedges)
+	assert.Equal(t, uint64(1), stats.Throttled)
+}
+
+func TestDo_Disabled(t *testing.T) {
+	t.Parallel()
+
+	h := hedge.New(&hedge.Config{MinDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	var calls atomic.Int64
+	_, err := hedge.Do(t.Context(), h, func(context.Context) (struct{}, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return struct{}{}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), calls.Load())
+}
+
+func TestDo_DelayRecomputed(t *testing.T) {
+	t.Parallel()
+
+	h := hedge.New(&hedge.Config{MinDelay: time.Millisecond, MaxDelay: time.Second})
+	do := func(n int, latency time.Duration) {
+		for range n {
+			_, err := hedge.Do(t.Context(), h, func(context.Context) (struct{}, error) {
+				time.Sleep(latency)
+				return struct{}{}, nil
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	// The maximum delay is used until enough latencies are observed
+	do(19, 0)
+	assert.Equal(t, time.Second, h.Delay())
+	do(1, 0)
+	assert.Equal(t, time.Millisecond, h.Delay())
+
+	// The delay is recomputed only after a batch of new latencies is observed
+	do(49, 5*time.Millisecond)
+	assert.Equal(t, time.Millisecond, h.Delay())
+	do(1, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, h.Delay(), 5*time.Millisecond)
+}
+
+func TestDo_HedgeLatency(t *testing.T) {
+	t.Parallel()
+
+	delay := 20 * time.Millisecond
+	h := hedge.New(&hedge.Config{MaxDelay: delay, MaxHedgesPerSecond: 100})
+
+	// The hedged requests win immediately, but the latencies observed include the hedging delay
+	for range 20 {
+		var calls atomic.Int64
+		_, err := hedge.Do(t.Context(), h, func(ctx context.Context) (struct{}, error) {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+				return struct{}{}, ctx.Err()
+			}
+			return struct{}{}, nil
+		})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, delay, h.Delay())
+}
@@ -0,0 +1,81 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fra98/pokedex/pkg/client/breaker"
+)
+
+var _ Client = &CircuitBreakerPokeAPIClient{} // check if it implements the Client interface.
+
+// CircuitBreakerPokeAPIClient represents a client that interacts with the PokeAPI through a circuit breaker,
+// failing fast while the PokeAPI is unavailable.
+type CircuitBreakerPokeAPIClient struct {
+	client  Client
+	breaker *breaker.Breaker
+}
+
+// NewCircuitBreakerPokeAPIClient returns a new PokeAPIClient protected by the given circuit breaker.
+func NewCircuitBreakerPokeAPIClient(client Client, b *breaker.Breaker) *CircuitBreakerPokeAPIClient {
+	return &CircuitBreakerPokeAPIClient{
+		client:  client,
+		breaker: b,
+	}
+}
+
+// GetPokemonSpecies returns a Pokemon species by name.
+func (c *CircuitBreakerPokeAPIClient) GetPokemonSpecies(ctx context.Context, name string) (*PokemonSpecies, error) {
+	species, err := breaker.Do(c.breaker, func() (*PokemonSpecies, error) {
+		return c.client.GetPokemonSpecies(ctx, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	return species, nil
+}
+
+// GetPokemonSpeciesConditional returns a Pokemon species by name, if modified according to the validators.
+func (c *CircuitBreakerPokeAPIClient) GetPokemonSpeciesConditional(ctx context.Context, name string,
+	validators Validators) (*ConditionalSpecies, error) {
+	res, err := breaker.Do(c.breaker, func() (*ConditionalSpecies, error) {
+		return c.client.GetPokemonSpeciesConditional(ctx, name, validators)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	return res, nil
+}
+
+// ListPokemonSpecies returns a page of the list of Pokemon species.
+func (c *CircuitBreakerPokeAPIClient) ListPokemonSpecies(ctx context.Context, offset, limit int) (*NamedAPIResourceList, error) {
+	list, err := breaker.Do(c.breaker, func() (*NamedAPIResourceList, error) {
+		return c.client.ListPokemonSpecies(ctx, offset, limit)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon species: %w", err)
+	}
+	return list, nil
+}
+
+// ListTypes returns the list of Pokemon types.
+func (c *CircuitBreakerPokeAPIClient) ListTypes(ctx context.Context) (*NamedAPIResourceList, error) {
+	list, err := breaker.Do(c.breaker, func() (*NamedAPIResourceList, error) {
+		return c.client.ListTypes(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon types: %w", err)
+	}
+	return list, nil
+}
+
+// GetType returns a Pokemon type by name.
+func (c *CircuitBreakerPokeAPIClient) GetType(ctx context.Context, name string) (*Type, error) {
+	t, err := breaker.Do(c.breaker, func() (*Type, error) {
+		return c.client.GetType(ctx, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon type: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,81 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fra98/pokedex/pkg/client/bulkhead"
+)
+
+var _ Client = &BulkheadPokeAPIClient{} // check if it implements the Client interface.
+
+// BulkheadPokeAPIClient represents a client that interacts with the PokeAPI through a bulkhead,
+// limiting the concurrent requests so that a slow PokeAPI cannot starve the whole server.
+type BulkheadPokeAPIClient struct {
+	client   Client
+	bulkhead *bulkhead.Bulkhead
+}
+
+// NewBulkheadPokeAPIClient returns a new PokeAPIClient limiting the concurrent requests with the given bulkhead.
+func NewBulkheadPokeAPIClient(client Client, b *bulkhead.Bulkhead) *BulkheadPokeAPIClient {
+	return &BulkheadPokeAPIClient{
+		client:   client,
+		bulkhead: b,
+	}
+}
+
+// GetPokemonSpecies returns a Pokemon species by name.
+func (c *BulkheadPokeAPIClient) GetPokemonSpecies(ctx context.Context, name string) (*PokemonSpecies, error) {
+	species, err := bulkhead.Do(ctx, c.bulkhead, func() (*PokemonSpecies, error) {
+		return c.client.GetPokemonSpecies(ctx, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	return species, nil
+}
+
+// GetPokemonSpeciesConditional returns a Pokemon species by name, if modified according to the validators.
+func (c *BulkheadPokeAPIClient) GetPokemonSpeciesConditional(ctx context.Context, name string,
+	validators Validators) (*ConditionalSpecies, error) {
+	res, err := bulkhead.Do(ctx, c.bulkhead, func() (*ConditionalSpecies, error) {
+		return c.client.GetPokemonSpeciesConditional(ctx, name, validators)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	return res, nil
+}
+
+// ListPokemonSpecies returns a page of the list of Pokemon species.
+func (c *BulkheadPokeAPIClient) ListPokemonSpecies(ctx context.Context, offset, limit int) (*NamedAPIResourceList, error) {
+	list, err := bulkhead.Do(ctx, c.bulkhead, func() (*NamedAPIResourceList, error) {
+		return c.client.ListPokemonSpecies(ctx, offset, limit)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon species: %w", err)
+	}
+	return list, nil
+}
+
+// ListTypes returns the list of Pokemon types.
+func (c *BulkheadPokeAPIClient) ListTypes(ctx context.Context) (*NamedAPIResourceList, error) {
+	list, err := bulkhead.Do(ctx, c.bulkhead, func() (*NamedAPIResourceList, error) {
+		return c.client.ListTypes(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon types: %w", err)
+	}
+	return list, nil
+}
+
+// GetType returns a Pokemon type by name.
+func (c *BulkheadPokeAPIClient) GetType(ctx context.Context, name string) (*Type, error) {
+	t, err := bulkhead.Do(ctx, c.bulkhead, func() (*Type, error) {
+		return c.client.GetType(ctx, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon type: %w", err)
+	}
+	return t, nil
+}
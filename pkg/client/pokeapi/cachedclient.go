@@ -3,51 +3,169 @@ package pokeapi
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
-	"github.com/patrickmn/go-cache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/tracing"
 )
 
-var _ Client = &CachedPokeAPIClient{} // check if it implements the Client interface.
+// SpeciesCacheKeyPrefix is the prefix of the cache keys of the Pokemon species.
+const SpeciesCacheKeyPrefix = "pokeapi:species:"
+
+var (
+	_ Client      = &CachedPokeAPIClient{} // check if it implements the Client interface.
+	_ cache.Sizer = &CachedSpecies{}       // check if it implements the Sizer interface.
+)
+
+// CachedSpecies represents a Pokemon species stored in the cache, along with its upstream validators.
+type CachedSpecies struct {
+	Species    *PokemonSpecies `json:"species"`
+	Validators Validators      `json:"validators"`
+	FreshUntil time.Time       `json:"freshUntil"`
+}
+
+// cachedSpeciesOverhead is the estimated memory footprint of the fixed-size fields of a cached species.
+const cachedSpeciesOverhead = 128
+
+// Size returns the estimated memory footprint of the cached species, in bytes, without encoding it.
+func (s *CachedSpecies) Size() int {
+	size := cachedSpeciesOverhead + len(s.Validators.ETag) + len(s.Validators.LastModified)
+	if s.Species != nil {
+		size += s.Species.Size()
+	}
+	return size
+}
 
 // CachedPokeAPIClient represents a client that interacts with the PokeAPI and caches the results.
+// Expired species are kept in the cache for the revalidation window, and revalidated with a conditional request
+// on the next access: if the upstream replies 304 Not Modified, the cached species is simply considered fresh again.
 type CachedPokeAPIClient struct {
-	client Client
-	cache  *cache.Cache
+	client             Client
+	cache              cache.Cache
+	ttl                time.Duration
+	revalidationWindow time.Duration
 }
 
 // NewCachedPokeAPIClient returns a new cached PokeAPIClient.
-func NewCachedPokeAPIClient(client Client, timeoutExpiration, cleanupInterval time.Duration) *CachedPokeAPIClient {
+// The species are considered fresh for the given ttl, and then kept for the revalidation window (0 disables revalidation).
+func NewCachedPokeAPIClient(client Client, c cache.Cache, ttl, revalidationWindow time.Duration) *CachedPokeAPIClient {
 	return &CachedPokeAPIClient{
-		client: client,
-		cache:  cache.New(timeoutExpiration, cleanupInterval),
+		client:             client,
+		cache:              c,
+		ttl:                ttl,
+		revalidationWindow: revalidationWindow,
 	}
 }
 
 // GetPokemonSpecies returns a Pokemon species by name.
-func (c *CachedPokeAPIClient) GetPokemonSpecies(ctx context.Context, name string) (*PokemonSpecies, error) {
-	cacheKey := "pokeapi:species:" + name
+func (c *CachedPokeAPIClient) GetPokemonSpecies(ctx context.Context, name string) (_ *PokemonSpecies, err error) {
+	ctx, span := tracing.Start(ctx, "CachedPokeAPIClient.GetPokemonSpecies", trace.WithAttributes(attribute.String("pokemon.name", name)))
+	defer func() { tracing.End(span, err) }()
+
+	cacheKey := SpeciesCacheKeyPrefix + name
 
 	// Try to get from cache first
-	if cachedData, found := c.cache.Get(cacheKey); found {
-		cachedSpecies, ok := cachedData.(*PokemonSpecies)
-		if ok {
-			return cachedSpecies, nil
+	var validators Validators
+	cached := c.getCached(ctx, cacheKey)
+	fresh := cached != nil && time.Now().Before(cached.FreshUntil)
+	span.SetAttributes(attribute.Bool("cache.hit", fresh), attribute.Bool("cache.stale", cached != nil && !fresh))
+	if cached != nil {
+		if fresh {
+			cache.ObserveExpiration(ctx, cached.FreshUntil)
+			return cached.Species, nil
 		}
-		// Otherwise, remove the invalid cache entry and proceed
-		log.Printf("Invalid cache entry for key %q", cacheKey)
-		c.cache.Delete(cacheKey)
+		// The cached species is stale, revalidate it if possible
+		validators = cached.Validators
 	}
 
-	// Call the underlying client
-	species, err := c.client.GetPokemonSpecies(ctx, name)
+	// Call the underlying client, conditionally if the cached species has validators
+	res, err := c.client.GetPokemonSpeciesConditional(ctx, name, validators)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
 	}
 
+	species := res.Species
+	span.SetAttributes(attribute.Bool("cache.revalidated", res.NotModified))
+	if res.NotModified {
+		if cached == nil {
+			return nil, fmt.Errorf("unexpected not modified response for an uncached species: %w", errors.ErrFailedRequest)
+		}
+		species = cached.Species
+	}
+
 	// Cache the result
-	c.cache.Set(cacheKey, species, 0)
+	c.setCached(cacheKey, species, res.Validators)
 
 	return species, nil
 }
+
+// GetPokemonSpeciesConditional returns a Pokemon species by name. The request is not cached.
+func (c *CachedPokeAPIClient) GetPokemonSpeciesConditional(ctx context.Context, name string, validators Validators) (*ConditionalSpecies, error) {
+	res, err := c.client.GetPokemonSpeciesConditional(ctx, name, validators)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	return res, nil
+}
+
+// ListPokemonSpecies returns a page of the list of Pokemon species. The list is not cached.
+func (c *CachedPokeAPIClient) ListPokemonSpecies(ctx context.Context, offset, limit int) (*NamedAPIResourceList, error) {
+	list, err := c.client.ListPokemonSpecies(ctx, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon species: %w", err)
+	}
+	return list, nil
+}
+
+// ListTypes returns the list of Pokemon types. The list is not cached.
+func (c *CachedPokeAPIClient) ListTypes(ctx context.Context) (*NamedAPIResourceList, error) {
+	list, err := c.client.ListTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon types: %w", err)
+	}
+	return list, nil
+}
+
+// GetType returns a Pokemon type by name. The type is not cached, since it is only used to build the catalog.
+func (c *CachedPokeAPIClient) GetType(ctx context.Context, name string) (*Type, error) {
+	t, err := c.client.GetType(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon type: %w", err)
+	}
+	return t, nil
+}
+
+// getCached returns the cached species for the key, or nil if not found.
+func (c *CachedPokeAPIClient) getCached(ctx context.Context, cacheKey string) *CachedSpecies {
+	cachedData, found := c.cache.Get(cacheKey)
+	if !found {
+		return nil
+	}
+	cachedSpecies, ok := cachedData.(*CachedSpecies)
+	if ok {
+		return cachedSpecies
+	}
+	// Otherwise, remove the invalid cache entry and proceed
+	slog.WarnContext(ctx, "Invalid cache entry", "key", cacheKey)
+	c.cache.Delete(cacheKey)
+	return nil
+}
+
+// setCached stores the species in the cache, keeping it after its expiration only if it can be revalidated.
+func (c *CachedPokeAPIClient) setCached(cacheKey string, species *PokemonSpecies, validators Validators) {
+	ttl := c.ttl
+	if !validators.IsZero() {
+		ttl += c.revalidationWindow
+	}
+
+	c.cache.Set(cacheKey, &CachedSpecies{
+		Species:    species,
+		Validators: validators,
+		FreshUntil: time.Now().Add(c.ttl),
+	}, ttl)
+}
@@ -0,0 +1,113 @@
+package pokeapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+)
+
+const (
+	testETag         = `"species-v1"`
+	testLastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+)
+
+func TestCachedPokeAPIClient_Revalidation(t *testing.T) {
+	t.Parallel()
+
+	var fullResponses, notModifiedResponses atomic.Int64
+	pokeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reply 304 if the client already has the current version of the species
+		if r.Header.Get("If-None-Match") == testETag {
+			assert.Equal(t, testLastModified, r.Header.Get("If-Modified-Since"))
+			notModifiedResponses.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		fullResponses.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", testETag)
+		w.Header().Set("Last-Modified", testLastModified)
+		_, err := w.Write([]byte(`{"name": "mewtwo", "is_legendary": true}`))
+		assert.NoError(t, err)
+	}))
+	defer pokeServer.Close()
+
+	ttl := 20 * time.Millisecond
+	client := pokeapi.NewCachedPokeAPIClient(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), cache.NewMemoryCache(ttl, time.Hour), ttl, time.Hour)
+
+	// First request downloads the full species
+	species, err := client.GetPokemonSpecies(t.Context(), "mewtwo")
+	require.NoError(t, err)
+	assert.Equal(t, "mewtwo", species.Name)
+
+	// Second request is served by the cache while fresh
+	_, err = client.GetPokemonSpecies(t.Context(), "mewtwo")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), fullResponses.Load())
+	assert.Equal(t, int64(0), notModifiedResponses.Load())
+
+	// Once expired, the species is revalidated and the cached one is returned
+	time.Sleep(2 * ttl)
+	species, err = client.GetPokemonSpecies(t.Context(), "mewtwo")
+	require.NoError(t, err)
+	assert.Equal(t, "mewtwo", species.Name)
+	assert.True(t, species.IsLegendary)
+	assert.Equal(t, int64(1), fullResponses.Load())
+	assert.Equal(t, int64(1), notModifiedResponses.Load())
+
+	// After the revalidation, the species is fresh again
+	_, err = client.GetPokemonSpecies(t.Context(), "mewtwo")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), notModifiedResponses.Load())
+}
+
+func TestCachedPokeAPIClient_NoRevalidationWindow(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int64
+	pokeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", testETag)
+		_, err := w.Write([]byte(`{"name": "mewtwo"}`))
+		assert.NoError(t, err)
+	}))
+	defer pokeServer.Close()
+
+	ttl := 20 * time.Millisecond
+	client := pokeapi.NewCachedPokeAPIClient(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), cache.NewMemoryCache(ttl, time.Hour), ttl, 0)
+
+	_, err := client.GetPokemonSpecies(t.Context(), "mewtwo")
+	require.NoError(t, err)
+
+	// Without revalidation window, expired species are downloaded again
+	time.Sleep(2 * ttl)
+	_, err = client.GetPokemonSpecies(t.Context(), "mewtwo")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), requests.Load())
+}
+
+func TestCachedSpecies_Size(t *testing.T) {
+	t.Parallel()
+
+	species := &pokeapi.CachedSpecies{
+		Species:    &pokeapi.PokemonSpecies{Name: "mewtwo", Habitat: pokeapi.Habitat{Name: "rare"}},
+		Validators: pokeapi.Validators{ETag: testETag, LastModified: testLastModified},
+	}
+	size := species.Size()
+
+	// The estimate grows with the flavor texts, which make up most of the species
+	species.Species.FlavorTextEntries = []pokeapi.FlavorTextEntry{
+		{FlavorText: "It was created by a scientist after years of horrific gene splicing.", Language: pokeapi.Language{Name: "en"}},
+	}
+	assert.Greater(t, species.Size(), size+len(species.Species.FlavorTextEntries[0].FlavorText))
+}
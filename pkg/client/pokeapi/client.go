@@ -5,15 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
+	"net/url"
+	"strconv"
 
 	"k8s.io/utils/ptr"
 
+	"github.com/fra98/pokedex/pkg/client/transport"
 	"github.com/fra98/pokedex/pkg/errors"
 )
 
 const defaultBaseURL = "https://pokeapi.co/api/v2"
 
+// upstreamName is the name of the upstream API carried by the returned errors.
+const upstreamName = "pokeapi"
+
 var _ Client = &PokeAPIClient{} // check if it implements the Client interface.
 
 // PokeAPIClient represents a client that interacts with the PokeAPI.
@@ -23,38 +28,119 @@ type PokeAPIClient struct { //nolint:revive // avoid conflict with the interface
 }
 
 // NewPokeAPIClient returns a new PokeAPIClient.
-func NewPokeAPIClient(baseURL *string) *PokeAPIClient {
+// The HTTP client is configured according to the given transport configuration, or with defaults if nil.
+func NewPokeAPIClient(baseURL *string, cfg *transport.Config) *PokeAPIClient {
 	return &PokeAPIClient{
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		httpClient: transport.NewClient(cfg),
 		baseURL:    ptr.Deref(baseURL, defaultBaseURL),
 	}
 }
 
 // GetPokemonSpecies returns a Pokemon species by name.
 func (c *PokeAPIClient) GetPokemonSpecies(ctx context.Context, name string) (*PokemonSpecies, error) {
-	url := c.baseURL + "/pokemon-species/" + name
+	res, err := c.GetPokemonSpeciesConditional(ctx, name, Validators{})
+	if err != nil {
+		return nil, err
+	}
+	return res.Species, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+// GetPokemonSpeciesConditional returns a Pokemon species by name, sending the validators as
+// If-None-Match and If-Modified-Since headers. If the upstream replies 304 Not Modified, no species is returned.
+func (c *PokeAPIClient) GetPokemonSpeciesConditional(ctx context.Context, name string, validators Validators) (*ConditionalSpecies, error) {
+	var species PokemonSpecies
+	resValidators, notModified, err := c.get(ctx, "/pokemon-species/"+name, validators, &species)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to get pokemon species: %w", err)
+	}
+	if notModified {
+		return &ConditionalSpecies{Validators: resValidators, NotModified: true}, nil
+	}
+	return &ConditionalSpecies{Species: &species, Validators: resValidators}, nil
+}
+
+// ListPokemonSpecies returns a page of the list of Pokemon species.
+func (c *PokeAPIClient) ListPokemonSpecies(ctx context.Context, offset, limit int) (*NamedAPIResourceList, error) {
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+
+	var list NamedAPIResourceList
+	if _, _, err := c.get(ctx, "/pokemon-species/?"+query.Encode(), Validators{}, &list); err != nil {
+		return nil, fmt.Errorf("failed to list pokemon species: %w", err)
+	}
+	return &list, nil
+}
+
+// ListTypes returns the list of Pokemon types.
+func (c *PokeAPIClient) ListTypes(ctx context.Context) (*NamedAPIResourceList, error) {
+	var list NamedAPIResourceList
+	if _, _, err := c.get(ctx, "/type/?limit=100", Validators{}, &list); err != nil {
+		return nil, fmt.Errorf("failed to list types: %w", err)
+	}
+	return &list, nil
+}
+
+// GetType returns a Pokemon type by name, along with the Pokemon having it.
+func (c *PokeAPIClient) GetType(ctx context.Context, name string) (*Type, error) {
+	var t Type
+	if _, _, err := c.get(ctx, "/type/"+name, Validators{}, &t); err != nil {
+		return nil, fmt.Errorf("failed to get type: %w", err)
+	}
+	return &t, nil
+}
+
+// Ping checks whether the PokeAPI is reachable.
+func (c *PokeAPIClient) Ping(ctx context.Context) error {
+	if err := transport.Ping(ctx, c.httpClient, c.baseURL+"/"); err != nil {
+		return fmt.Errorf("PokeAPI unreachable: %w", err)
+	}
+	return nil
+}
+
+// get sends a GET request to the given path, conditional if validators are provided, and decodes the JSON response into out.
+// It returns the validators of the response, and whether the resource has not been modified.
+func (c *PokeAPIClient) get(ctx context.Context, path string, validators Validators, out any) (Validators, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, http.NoBody)
+	if err != nil {
+		return Validators{}, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return Validators{}, false, fmt.Errorf("failed to send request: %w", errors.NewUpstreamError(upstreamName, 0, err))
 	}
 	defer resp.Body.Close()
 
+	resValidators := Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+
 	switch resp.StatusCode {
 	case http.StatusOK:
-		var species PokemonSpecies
-		if err := json.NewDecoder(resp.Body).Decode(&species); err != nil {
-			return nil, fmt.Errorf("failed to decode response: %w", err)
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return Validators{}, false, fmt.Errorf("failed to decode response: %w",
+				errors.NewUpstreamError(upstreamName, resp.StatusCode, fmt.Errorf("%w: %w", errors.ErrInvalidResponse, err)))
+		}
+		return resValidators, false, nil
+	case http.StatusNotModified:
+		// Validators may be omitted in a 304 response, in which case the provided ones are still valid
+		if resValidators.IsZero() {
+			resValidators = validators
 		}
-		return &species, nil
+		return resValidators, true, nil
 	case http.StatusNotFound:
-		return nil, fmt.Errorf("pokemon species not found: %w", errors.ErrResourceNotFound)
+		return Validators{}, false, fmt.Errorf("resource not found: %w", errors.NewUpstreamError(upstreamName, resp.StatusCode, errors.ErrResourceNotFound))
+	case http.StatusTooManyRequests:
+		return Validators{}, false, fmt.Errorf("too many requests: %w", errors.NewUpstreamError(upstreamName, resp.StatusCode, errors.ErrRateLimitExceeded))
 	default:
-		return nil, fmt.Errorf("failed to get pokemon species (code: %d): %w", resp.StatusCode, errors.ErrFailedRequest)
+		return Validators{}, false, fmt.Errorf("unexpected response: %w", errors.NewUpstreamError(upstreamName, resp.StatusCode, errors.ErrFailedRequest))
 	}
 }
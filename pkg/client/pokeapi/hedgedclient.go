@@ -0,0 +1,76 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fra98/pokedex/pkg/client/hedge"
+)
+
+var _ Client = &HedgedPokeAPIClient{} // check if it implements the Client interface.
+
+// HedgedPokeAPIClient represents a client that hedges the slow requests to the PokeAPI,
+// firing a second identical request and taking whichever result arrives first.
+type HedgedPokeAPIClient struct {
+	client Client
+	hedger *hedge.Hedger
+}
+
+// NewHedgedPokeAPIClient returns a new PokeAPIClient hedging the requests with the given hedger.
+func NewHedgedPokeAPIClient(client Client, h *hedge.Hedger) *HedgedPokeAPIClient {
+	return &HedgedPokeAPIClient{
+		client: client,
+		hedger: h,
+	}
+}
+
+// GetPokemonSpecies returns a Pokemon species by name.
+func (c *HedgedPokeAPIClient) GetPokemonSpecies(ctx context.Context, name string) (*PokemonSpecies, error) {
+	species, err := hedge.Do(ctx, c.hedger, func(ctx context.Context) (*PokemonSpecies, error) {
+		return c.client.GetPokemonSpecies(ctx, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	return species, nil
+}
+
+// GetPokemonSpeciesConditional returns a Pokemon species by name, if modified according to the validators.
+func (c *HedgedPokeAPIClient) GetPokemonSpeciesConditional(ctx context.Context, name string,
+	validators Validators) (*ConditionalSpecies, error) {
+	res, err := hedge.Do(ctx, c.hedger, func(ctx context.Context) (*ConditionalSpecies, error) {
+		return c.client.GetPokemonSpeciesConditional(ctx, name, validators)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	return res, nil
+}
+
+// ListPokemonSpecies returns a page of the list of Pokemon species.
+// Listing is not latency sensitive (it is used by the cache warm-up), hence it is not hedged.
+func (c *HedgedPokeAPIClient) ListPokemonSpecies(ctx context.Context, offset, limit int) (*NamedAPIResourceList, error) {
+	list, err := c.client.ListPokemonSpecies(ctx, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon species: %w", err)
+	}
+	return list, nil
+}
+
+// ListTypes returns the list of Pokemon types. It is used to build the catalog, hence it is not hedged.
+func (c *HedgedPokeAPIClient) ListTypes(ctx context.Context) (*NamedAPIResourceList, error) {
+	list, err := c.client.ListTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon types: %w", err)
+	}
+	return list, nil
+}
+
+// GetType returns a Pokemon type by name. It is used to build the catalog, hence it is not hedged.
+func (c *HedgedPokeAPIClient) GetType(ctx context.Context, name string) (*Type, error) {
+	t, err := c.client.GetType(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon type: %w", err)
+	}
+	return t, nil
+}
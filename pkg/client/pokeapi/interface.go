@@ -5,4 +5,10 @@ import "context"
 // Client is an interface that defines the methods to retrieve Pokemon information from an API.
 type Client interface {
 	GetPokemonSpecies(ctx context.Context, name string) (*PokemonSpecies, error)
+	// GetPokemonSpeciesConditional retrieves a Pokemon species only if it has been modified
+	// according to the given validators. Zero validators make it equivalent to GetPokemonSpecies.
+	GetPokemonSpeciesConditional(ctx context.Context, name string, validators Validators) (*ConditionalSpecies, error)
+	ListPokemonSpecies(ctx context.Context, offset, limit int) (*NamedAPIResourceList, error)
+	ListTypes(ctx context.Context) (*NamedAPIResourceList, error)
+	GetType(ctx context.Context, name string) (*Type, error)
 }
@@ -0,0 +1,90 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/metrics"
+)
+
+// metricsClientName is the client label of the PokeAPI metrics.
+const metricsClientName = "pokeapi"
+
+var _ Client = &InstrumentedPokeAPIClient{} // check if it implements the Client interface.
+
+// InstrumentedPokeAPIClient represents a client that records the count and latency of the requests to the PokeAPI,
+// by operation and outcome.
+type InstrumentedPokeAPIClient struct {
+	client  Client
+	metrics *metrics.Metrics
+}
+
+// NewInstrumentedPokeAPIClient returns a new PokeAPIClient recording the requests to the given metrics.
+func NewInstrumentedPokeAPIClient(client Client, m *metrics.Metrics) *InstrumentedPokeAPIClient {
+	return &InstrumentedPokeAPIClient{
+		client:  client,
+		metrics: m,
+	}
+}
+
+// GetPokemonSpecies returns a Pokemon species by name.
+func (c *InstrumentedPokeAPIClient) GetPokemonSpecies(ctx context.Context, name string) (*PokemonSpecies, error) {
+	start := time.Now()
+	species, err := c.client.GetPokemonSpecies(ctx, name)
+	c.metrics.ObserveUpstream(metricsClientName, "get_species", err, time.Since(start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	return species, nil
+}
+
+// GetPokemonSpeciesConditional returns a Pokemon species by name, if modified according to the validators.
+func (c *InstrumentedPokeAPIClient) GetPokemonSpeciesConditional(ctx context.Context, name string,
+	validators Validators) (*ConditionalSpecies, error) {
+	operation := "revalidate_species"
+	if validators.IsZero() {
+		// Without validators, the request is not conditional
+		operation = "get_species"
+	}
+	start := time.Now()
+	res, err := c.client.GetPokemonSpeciesConditional(ctx, name, validators)
+	c.metrics.ObserveUpstream(metricsClientName, operation, err, time.Since(start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	return res, nil
+}
+
+// ListPokemonSpecies returns a page of the list of Pokemon species.
+func (c *InstrumentedPokeAPIClient) ListPokemonSpecies(ctx context.Context, offset, limit int) (*NamedAPIResourceList, error) {
+	start := time.Now()
+	list, err := c.client.ListPokemonSpecies(ctx, offset, limit)
+	c.metrics.ObserveUpstream(metricsClientName, "list_species", err, time.Since(start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon species: %w", err)
+	}
+	return list, nil
+}
+
+// ListTypes returns the list of Pokemon types.
+func (c *InstrumentedPokeAPIClient) ListTypes(ctx context.Context) (*NamedAPIResourceList, error) {
+	start := time.Now()
+	list, err := c.client.ListTypes(ctx)
+	c.metrics.ObserveUpstream(metricsClientName, "list_types", err, time.Since(start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon types: %w", err)
+	}
+	return list, nil
+}
+
+// GetType returns a Pokemon type by name.
+func (c *InstrumentedPokeAPIClient) GetType(ctx context.Context, name string) (*Type, error) {
+	start := time.Now()
+	t, err := c.client.GetType(ctx, name)
+	c.metrics.ObserveUpstream(metricsClientName, "get_type", err, time.Since(start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon type: %w", err)
+	}
+	return t, nil
+}
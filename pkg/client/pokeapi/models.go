@@ -1,13 +1,32 @@
 package pokeapi
 
+import (
+	"strconv"
+	"strings"
+)
+
 // PokemonSpecies represents a Pokemon species.
 type PokemonSpecies struct {
+	ID                int               `json:"id"`
 	Name              string            `json:"name"`
 	IsLegendary       bool              `json:"is_legendary"`
 	Habitat           Habitat           `json:"habitat"`
+	Generation        NamedAPIResource  `json:"generation"`
 	FlavorTextEntries []FlavorTextEntry `json:"flavor_text_entries"`
 }
 
+// flavorTextEntryOverhead is the estimated memory footprint of the fixed-size fields of a flavor text entry.
+const flavorTextEntryOverhead = 32
+
+// Size returns the estimated memory footprint of the species, in bytes, i.e., mostly the length of its strings.
+func (s *PokemonSpecies) Size() int {
+	size := len(s.Name) + len(s.Habitat.Name) + len(s.Generation.Name) + len(s.Generation.URL)
+	for _, entry := range s.FlavorTextEntries {
+		size += flavorTextEntryOverhead + len(entry.FlavorText) + len(entry.Language.Name)
+	}
+	return size
+}
+
 // Habitat represents a habitat where a Pokemon species can be found.
 type Habitat struct {
 	Name string `json:"name"`
@@ -23,3 +42,59 @@ type FlavorTextEntry struct {
 type Language struct {
 	Name string `json:"name"`
 }
+
+// NamedAPIResourceList represents a paginated list of named resources.
+type NamedAPIResourceList struct {
+	Count    int                `json:"count"`
+	Next     *string            `json:"next"`
+	Previous *string            `json:"previous"`
+	Results  []NamedAPIResource `json:"results"`
+}
+
+// NamedAPIResource represents a reference to a named resource.
+type NamedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// ID returns the ID of the resource, i.e., the last segment of its URL (e.g., 25 for ".../pokemon-species/25/").
+// It returns 0 if the URL does not end with an ID.
+func (r NamedAPIResource) ID() int {
+	segments := strings.Split(strings.TrimSuffix(r.URL, "/"), "/")
+	id, err := strconv.Atoi(segments[len(segments)-1])
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// Type represents a Pokemon type, along with the Pokemon having it.
+type Type struct {
+	Name    string        `json:"name"`
+	Pokemon []TypePokemon `json:"pokemon"`
+}
+
+// TypePokemon represents a Pokemon having a type, in the given slot (1 for the primary type).
+type TypePokemon struct {
+	Slot    int              `json:"slot"`
+	Pokemon NamedAPIResource `json:"pokemon"`
+}
+
+// Validators represents the HTTP validators of a resource, used to send conditional requests.
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// IsZero returns true if no validator is set.
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// ConditionalSpecies represents the result of a conditional request for a Pokemon species.
+type ConditionalSpecies struct {
+	// Species is nil if the species has not been modified.
+	Species     *PokemonSpecies
+	Validators  Validators
+	NotModified bool
+}
@@ -0,0 +1,79 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+)
+
+var _ Client = &ObservedPokeAPIClient{} // check if it implements the Client interface.
+
+// SpeciesObserver is notified of every Pokemon species retrieved through an ObservedPokeAPIClient.
+type SpeciesObserver interface {
+	ObserveSpecies(species *PokemonSpecies)
+}
+
+// ObservedPokeAPIClient represents a client that notifies an observer of the retrieved Pokemon species,
+// e.g., to index them as they are fetched. Wrapped by a CachedPokeAPIClient, the observer is notified only of the species
+// fetched from the PokeAPI, including the ones modified upstream when revalidated, and not of the cache hits.
+type ObservedPokeAPIClient struct {
+	client   Client
+	observer SpeciesObserver
+}
+
+// NewObservedPokeAPIClient returns a new PokeAPIClient notifying the given observer of the retrieved species.
+func NewObservedPokeAPIClient(client Client, observer SpeciesObserver) *ObservedPokeAPIClient {
+	return &ObservedPokeAPIClient{
+		client:   client,
+		observer: observer,
+	}
+}
+
+// GetPokemonSpecies returns a Pokemon species by name.
+func (c *ObservedPokeAPIClient) GetPokemonSpecies(ctx context.Context, name string) (*PokemonSpecies, error) {
+	species, err := c.client.GetPokemonSpecies(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	c.observer.ObserveSpecies(species)
+	return species, nil
+}
+
+// GetPokemonSpeciesConditional returns a Pokemon species by name, if modified according to the validators.
+func (c *ObservedPokeAPIClient) GetPokemonSpeciesConditional(ctx context.Context, name string,
+	validators Validators) (*ConditionalSpecies, error) {
+	res, err := c.client.GetPokemonSpeciesConditional(ctx, name, validators)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon species: %w", err)
+	}
+	if res.Species != nil {
+		c.observer.ObserveSpecies(res.Species)
+	}
+	return res, nil
+}
+
+// ListPokemonSpecies returns a page of the list of Pokemon species.
+func (c *ObservedPokeAPIClient) ListPokemonSpecies(ctx context.Context, offset, limit int) (*NamedAPIResourceList, error) {
+	list, err := c.client.ListPokemonSpecies(ctx, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon species: %w", err)
+	}
+	return list, nil
+}
+
+// ListTypes returns the list of Pokemon types.
+func (c *ObservedPokeAPIClient) ListTypes(ctx context.Context) (*NamedAPIResourceList, error) {
+	list, err := c.client.ListTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pokemon types: %w", err)
+	}
+	return list, nil
+}
+
+// GetType returns a Pokemon type by name.
+func (c *ObservedPokeAPIClient) GetType(ctx context.Context, name string) (*Type, error) {
+	t, err := c.client.GetType(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Pokemon type: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,79 @@
+package pokeapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+)
+
+// recordingObserver is a SpeciesObserver recording the names of the observed species.
+type recordingObserver struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (o *recordingObserver) ObserveSpecies(species *pokeapi.PokemonSpecies) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.names = append(o.names, species.Name)
+}
+
+func (o *recordingObserver) observed() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.names
+}
+
+func TestObservedPokeAPIClient_InsideCache(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	etag := testETag
+	pokeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, err := w.Write([]byte(`{"name": "mewtwo"}`))
+		assert.NoError(t, err)
+	}))
+	defer pokeServer.Close()
+
+	observer := &recordingObserver{}
+	ttl := 20 * time.Millisecond
+	client := pokeapi.NewCachedPokeAPIClient(pokeapi.NewObservedPokeAPIClient(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), observer),
+		cache.NewMemoryCache(ttl, time.Hour), ttl, time.Hour)
+
+	// The species fetched from the PokeAPI is observed, but not the cache hits
+	for range 2 {
+		_, err := client.GetPokemonSpecies(t.Context(), "mewtwo")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"mewtwo"}, observer.observed())
+
+	// The species revalidated as not modified is not observed again
+	time.Sleep(2 * ttl)
+	_, err := client.GetPokemonSpecies(t.Context(), "mewtwo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mewtwo"}, observer.observed())
+
+	// The species modified upstream is observed again once revalidated
+	mu.Lock()
+	etag = `"species-v2"`
+	mu.Unlock()
+	time.Sleep(2 * ttl)
+	_, err = client.GetPokemonSpecies(t.Context(), "mewtwo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mewtwo", "mewtwo"}, observer.observed())
+}
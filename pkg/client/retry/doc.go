@@ -0,0 +1,3 @@
+// Package retry provides an HTTP transport retrying the failed requests to the upstream APIs,
+// with exponential backoff and jitter.
+package retry
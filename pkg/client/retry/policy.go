@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Policy contains the configuration of the retries.
+type Policy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// BackoffBase is the delay before the first retry, doubled at each subsequent retry.
+	BackoffBase time.Duration
+	// BackoffCap is the maximum delay between two attempts, unless a longer Retry-After is requested.
+	BackoffCap time.Duration
+	// Jitter is the fraction of the delay randomly subtracted from it, between 0 and 1.
+	Jitter float64
+	// RetryableStatusCodes are the response status codes triggering a retry.
+	RetryableStatusCodes []int
+	// RetryNetworkErrors enables the retries on network errors.
+	RetryNetworkErrors bool
+}
+
+// Without returns a copy of the policy where the given status codes are not retryable.
+func (p *Policy) Without(statusCodes ...int) *Policy {
+	policy := *p
+	policy.RetryableStatusCodes = slices.DeleteFunc(slices.Clone(p.RetryableStatusCodes), func(code int) bool {
+		return slices.Contains(statusCodes, code)
+	})
+	return &policy
+}
+
+// isRetryableStatus returns true if the status code triggers a retry. 404 Not Found is never retried.
+func (p *Policy) isRetryableStatus(statusCode int) bool {
+	return statusCode != http.StatusNotFound && slices.Contains(p.RetryableStatusCodes, statusCode)
+}
+
+// backoff returns the delay before the given retry (starting from 1), with exponential growth and jitter.
+func (p *Policy) backoff(retry int) time.Duration {
+	delay := p.BackoffBase
+	for i := 1; i < retry && (p.BackoffCap <= 0 || delay < p.BackoffCap); i++ {
+		delay *= 2
+	}
+	if p.BackoffCap > 0 {
+		delay = min(delay, p.BackoffCap)
+	}
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * p.Jitter * float64(delay)) //nolint:gosec // jitter does not need a secure random
+	}
+	return delay
+}
+
+// retryAfter returns the delay requested by the Retry-After header of the response, or 0 if absent or invalid.
+// The header can be either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
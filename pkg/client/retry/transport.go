@@ -0,0 +1,128 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+)
+
+var _ http.RoundTripper = &Transport{} // check if it implements the RoundTripper interface.
+
+// errNotRewindable represents an error when a request body can not be sent again.
+var errNotRewindable = errors.New("request body can not be rewound")
+
+// Transport is an http.RoundTripper retrying the failed requests according to a policy.
+// Retries respect the request context deadline: a retry is not attempted if its delay would exceed it.
+// The non-idempotent requests (e.g., POST) are retried on network errors only if they were not written,
+// since the upstream may have processed them otherwise.
+type Transport struct {
+	base   http.RoundTripper
+	policy *Policy
+}
+
+// NewTransport returns a new Transport retrying the requests sent through the base transport.
+// If base is nil, http.DefaultTransport is used.
+func NewTransport(base http.RoundTripper, policy *Policy) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, policy: policy}
+}
+
+// RoundTrip sends the request, retrying it on retryable failures.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := t.prepareAttempt(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		var written atomic.Bool
+		if !idempotent(req) {
+			attemptReq = attemptReq.WithContext(httptrace.WithClientTrace(attemptReq.Context(), &httptrace.ClientTrace{
+				WroteRequest: func(httptrace.WroteRequestInfo) { written.Store(true) },
+			}))
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.policy.MaxAttempts || !t.shouldRetry(ctx, resp, err, written.Load()) {
+			return resp, err //nolint:wrapcheck // the transport must return the errors as they are
+		}
+
+		delay := t.policy.backoff(attempt)
+		if resp != nil {
+			delay = max(delay, retryAfter(resp))
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// Not enough time left for another attempt
+			return resp, err //nolint:wrapcheck // the transport must return the errors as they are
+		}
+
+		attrs := []any{"url", req.URL.Redacted(), "attempt", attempt, "delay", delay}
+		if resp != nil {
+			attrs = append(attrs, "status", resp.StatusCode)
+			// Drain and close the body to reuse the connection
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			attrs = append(attrs, "error", err)
+		}
+		slog.DebugContext(ctx, "Retrying upstream request", attrs...)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("request canceled while waiting to retry: %w", ctx.Err())
+		}
+	}
+}
+
+// prepareAttempt returns the request to send for the given attempt, rewinding the body for the retries.
+func (t *Transport) prepareAttempt(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("unable to retry request: %w", errNotRewindable)
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("unable to rewind request body: %w", err)
+	}
+	attemptReq := req.Clone(req.Context())
+	attemptReq.Body = body
+	return attemptReq, nil
+}
+
+// shouldRetry returns true if the outcome of an attempt is retryable according to the policy.
+// Requests whose context is done are never retried, nor the non-idempotent ones failing after being written.
+func (t *Transport) shouldRetry(ctx context.Context, resp *http.Response, err error, written bool) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return t.policy.RetryNetworkErrors && !written
+	}
+	return t.policy.isRetryableStatus(resp.StatusCode)
+}
+
+// idempotent returns true if the request method is idempotent, as defined by RFC 9110,
+// so that the request can be sent again even if the upstream may have processed it.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
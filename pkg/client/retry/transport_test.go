@@ -0,0 +1,211 @@
+package retry_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/client/retry"
+)
+
+func testPolicy() *retry.Policy {
+	return &retry.Policy{
+		MaxAttempts:          3,
+		BackoffBase:          time.Millisecond,
+		BackoffCap:           5 * time.Millisecond,
+		Jitter:               0.5,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusNotFound},
+		RetryNetworkErrors:   true,
+	}
+}
+
+// newFlakyServer is a helper function to setup a test server replying with the given status codes, in order,
+// and then with 200 OK. It returns the test server and a counter of the received requests.
+func newFlakyServer(t *testing.T, header http.Header, statusCodes ...int) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every attempt must carry the whole request body
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "request body", string(body))
+
+		attempt := int(requests.Add(1))
+		if attempt <= len(statusCodes) {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(statusCodes[attempt-1])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return server, &requests
+}
+
+func doRequest(ctx context.Context, t *testing.T, client *http.Client, url string) (*http.Response, error) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader([]byte("request body")))
+	require.NoError(t, err)
+	return client.Do(req)
+}
+
+func TestTransport_RetrySuccess(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newFlakyServer(t, nil, http.StatusBadGateway, http.StatusBadGateway)
+	defer server.Close()
+
+	client := &http.Client{Transport: retry.NewTransport(nil, testPolicy())}
+	resp, err := doRequest(t.Context(), t, client, server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(3), requests.Load())
+}
+
+func TestTransport_MaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newFlakyServer(t, nil, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	defer server.Close()
+
+	client := &http.Client{Transport: retry.NewTransport(nil, testPolicy())}
+	resp, err := doRequest(t.Context(), t, client, server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int64(3), requests.Load())
+}
+
+func TestTransport_NotRetryable(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		policy     *retry.Policy
+		statusCode int
+	}{
+		"not_found_never_retried":      {policy: testPolicy(), statusCode: http.StatusNotFound},
+		"status_code_not_configured":   {policy: testPolicy(), statusCode: http.StatusInternalServerError},
+		"status_code_excluded_by_user": {policy: testPolicy().Without(http.StatusTooManyRequests), statusCode: http.StatusTooManyRequests},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server, requests := newFlakyServer(t, nil, tc.statusCode)
+			defer server.Close()
+
+			client := &http.Client{Transport: retry.NewTransport(nil, tc.policy)}
+			resp, err := doRequest(t.Context(), t, client, server.URL)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+			assert.Equal(t, int64(1), requests.Load())
+		})
+	}
+}
+
+func TestTransport_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{"Retry-After": []string{"1"}}
+
+	// Retry-After is honored when the deadline allows it
+	server, requests := newFlakyServer(t, header, http.StatusTooManyRequests)
+	defer server.Close()
+
+	client := &http.Client{Transport: retry.NewTransport(nil, testPolicy())}
+	start := time.Now()
+	resp, err := doRequest(t.Context(), t, client, server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(2), requests.Load())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+
+	// Retry-After exceeding the context deadline returns the failed response immediately
+	server, requests = newFlakyServer(t, header, http.StatusTooManyRequests)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 500*time.Millisecond)
+	defer cancel()
+
+	resp, err = doRequest(ctx, t, client, server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int64(1), requests.Load())
+}
+
+// countingTransport is an http.RoundTripper counting the attempts sent through the default transport.
+type countingTransport struct {
+	attempts atomic.Int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.attempts.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTransport_NetworkErrors(t *testing.T) {
+	t.Parallel()
+
+	// The dropping server closes the connections after reading the requests, without replying
+	dropping := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		conn, _, err := http.NewResponseController(w).Hijack()
+		assert.NoError(t, err)
+		conn.Close()
+	}))
+	t.Cleanup(dropping.Close)
+
+	// The closed server refuses the connections, before the requests are written
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	testCases := map[string]struct {
+		method   string
+		url      string
+		attempts int64
+	}{
+		"idempotent_written":         {method: http.MethodGet, url: dropping.URL, attempts: 3},
+		"idempotent_not_written":     {method: http.MethodGet, url: closed.URL, attempts: 3},
+		"not_idempotent_written":     {method: http.MethodPost, url: dropping.URL, attempts: 1},
+		"not_idempotent_not_written": {method: http.MethodPost, url: closed.URL, attempts: 3},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			base := &countingTransport{}
+			client := &http.Client{Transport: retry.NewTransport(base, testPolicy())}
+			req, err := http.NewRequestWithContext(t.Context(), tc.method, tc.url, bytes.NewReader([]byte("request body")))
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			if resp != nil {
+				resp.Body.Close()
+			}
+			require.Error(t, err)
+			assert.Equal(t, tc.attempts, base.attempts.Load())
+		})
+	}
+}
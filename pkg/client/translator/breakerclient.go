@@ -0,0 +1,36 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fra98/pokedex/pkg/client/breaker"
+)
+
+var _ Client = &CircuitBreakerTranslationClient{} // check if it implements the Client interface.
+
+// CircuitBreakerTranslationClient represents a client that interacts with a translation API through a circuit breaker,
+// failing fast while the translation API is unavailable.
+type CircuitBreakerTranslationClient struct {
+	client  Client
+	breaker *breaker.Breaker
+}
+
+// NewCircuitBreakerTranslationClient returns a new TranslationClient protected by the given circuit breaker.
+func NewCircuitBreakerTranslationClient(client Client, b *breaker.Breaker) *CircuitBreakerTranslationClient {
+	return &CircuitBreakerTranslationClient{
+		client:  client,
+		breaker: b,
+	}
+}
+
+// Translate returns a translated text according to the translation type.
+func (c *CircuitBreakerTranslationClient) Translate(ctx context.Context, text, translationType string) (string, error) {
+	translation, err := breaker.Do(c.breaker, func() (string, error) {
+		return c.client.Translate(ctx, text, translationType)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get translation: %w", err)
+	}
+	return translation, nil
+}
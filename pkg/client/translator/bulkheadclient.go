@@ -0,0 +1,36 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fra98/pokedex/pkg/client/bulkhead"
+)
+
+var _ Client = &BulkheadTranslationClient{} // check if it implements the Client interface.
+
+// BulkheadTranslationClient represents a client that interacts with a translation API through a bulkhead,
+// limiting the concurrent requests so that a slow translation API cannot starve the whole server.
+type BulkheadTranslationClient struct {
+	client   Client
+	bulkhead *bulkhead.Bulkhead
+}
+
+// NewBulkheadTranslationClient returns a new TranslationClient limiting the concurrent requests with the given bulkhead.
+func NewBulkheadTranslationClient(client Client, b *bulkhead.Bulkhead) *BulkheadTranslationClient {
+	return &BulkheadTranslationClient{
+		client:   client,
+		bulkhead: b,
+	}
+}
+
+// Translate returns a translated text according to the translation type.
+func (c *BulkheadTranslationClient) Translate(ctx context.Context, text, translationType string) (string, error) {
+	translation, err := bulkhead.Do(ctx, c.bulkhead, func() (string, error) {
+		return c.client.Translate(ctx, text, translationType)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get translation: %w", err)
+	}
+	return translation, nil
+}
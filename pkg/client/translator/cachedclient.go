@@ -3,43 +3,58 @@ package translator
 import (
 	"context"
 	"fmt"
-	"log"
-	"time"
+	"log/slog"
 
-	"github.com/patrickmn/go-cache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/tracing"
 )
 
+// CacheKeyPrefix is the prefix of the cache keys of the translations.
+const CacheKeyPrefix = "translation:"
+
 var _ Client = &CachedTranslationClient{} // check if it implements the Client interface.
 
 // CachedTranslationClient represents a client that interacts with a translation API and caches the results.
 type CachedTranslationClient struct {
 	client Client
-	cache  *cache.Cache
+	cache  cache.Cache
 }
 
 // NewCachedTranslationClient returns a new cached TranslationClient.
-func NewCachedTranslationClient(client Client, timeoutExpiration, cleanupInterval time.Duration) *CachedTranslationClient {
+func NewCachedTranslationClient(client Client, c cache.Cache) *CachedTranslationClient {
 	return &CachedTranslationClient{
 		client: client,
-		cache:  cache.New(timeoutExpiration, cleanupInterval),
+		cache:  c,
 	}
 }
 
 // Translate returns a translated text according to the translation type.
-func (c *CachedTranslationClient) Translate(ctx context.Context, text, translationType string) (string, error) {
-	cacheKey := "translation:" + translationType + ":" + text
+func (c *CachedTranslationClient) Translate(ctx context.Context, text, translationType string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "CachedTranslationClient.Translate", trace.WithAttributes(attribute.String("translation.type", translationType)))
+	defer func() { tracing.End(span, err) }()
+
+	cacheKey := CacheKeyPrefix + translationType + ":" + text
 
 	// Try to get from cache first
 	if cachedData, found := c.cache.Get(cacheKey); found {
 		cachedTranslation, ok := cachedData.(string)
 		if ok {
+			span.SetAttributes(attribute.Bool("cache.hit", true))
+			if entry, found := c.cache.Peek(cacheKey); found {
+				cache.ObserveExpiration(ctx, entry.ExpiresAt)
+			}
 			return cachedTranslation, nil
 		}
 		// Otherwise, remove the invalid cache entry and proceed
-		log.Printf("Invalid cache entry for key %q", cacheKey)
+		slog.WarnContext(ctx, "Invalid cache entry", "key", cacheKey)
 		c.cache.Delete(cacheKey)
 	}
 
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+
 	// Call the underlying client
 	translation, err := c.client.Translate(ctx, text, translationType)
 	if err != nil {
@@ -47,7 +62,7 @@ func (c *CachedTranslationClient) Translate(ctx context.Context, text, translati
 	}
 
 	// Cache the result
-	c.cache.Set(cacheKey, translation, 0)
+	c.cache.Set(cacheKey, translation, cache.DefaultExpiration)
 
 	return translation, nil
 }
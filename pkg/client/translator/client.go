@@ -6,16 +6,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 
 	"k8s.io/utils/ptr"
 
+	"github.com/fra98/pokedex/pkg/client/transport"
 	"github.com/fra98/pokedex/pkg/consts"
 	"github.com/fra98/pokedex/pkg/errors"
 )
 
 const defaultBaseURL = "https://api.funtranslations.com"
 
+// upstreamName is the name of the upstream API carried by the returned errors.
+const upstreamName = "translator"
+
 var _ Client = &FunTranslationClient{} // check if it implements the Client interface.
 
 // FunTranslationClient represents a client that interacts with the FunTranslations API.
@@ -25,9 +28,17 @@ type FunTranslationClient struct {
 }
 
 // NewFunTranslationClient returns a new FunTranslations client.
-func NewFunTranslationClient(baseURL *string) *FunTranslationClient {
+// The HTTP client is configured according to the given transport configuration, or with defaults if nil.
+// Rate limited requests are never retried, since the FunTranslations rate limit is per hour.
+func NewFunTranslationClient(baseURL *string, cfg *transport.Config) *FunTranslationClient {
+	if cfg != nil && cfg.Retry != nil {
+		config := *cfg
+		config.Retry = cfg.Retry.Without(http.StatusTooManyRequests)
+		cfg = &config
+	}
+
 	return &FunTranslationClient{
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		httpClient: transport.NewClient(cfg),
 		baseURL:    ptr.Deref(baseURL, defaultBaseURL),
 	}
 }
@@ -63,27 +74,36 @@ func (c *FunTranslationClient) Translate(ctx context.Context, text, translationT
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return "", fmt.Errorf("failed to send request: %w", errors.NewUpstreamError(upstreamName, 0, err))
 	}
 	defer resp.Body.Close()
 
 	// Handle rate limit exceeded error
 	if resp.StatusCode == http.StatusTooManyRequests {
-		return "", fmt.Errorf("failed to translate text: %w", errors.ErrRateLimitExceeded)
+		return "", fmt.Errorf("failed to translate text: %w", errors.NewUpstreamError(upstreamName, resp.StatusCode, errors.ErrRateLimitExceeded))
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to translate text (code: %d): %w", resp.StatusCode, errors.ErrFailedRequest)
+		return "", fmt.Errorf("failed to translate text: %w", errors.NewUpstreamError(upstreamName, resp.StatusCode, errors.ErrFailedRequest))
 	}
 
 	var translation translationResponse
 	if err := json.NewDecoder(resp.Body).Decode(&translation); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return "", fmt.Errorf("failed to decode response: %w",
+			errors.NewUpstreamError(upstreamName, resp.StatusCode, fmt.Errorf("%w: %w", errors.ErrInvalidResponse, err)))
 	}
 
 	return translation.Contents.Translated, nil
 }
 
+// Ping checks whether the translation API is reachable, without consuming the translation quota.
+func (c *FunTranslationClient) Ping(ctx context.Context) error {
+	if err := transport.Ping(ctx, c.httpClient, c.baseURL+"/"); err != nil {
+		return fmt.Errorf("translation API unreachable: %w", err)
+	}
+	return nil
+}
+
 func (c *FunTranslationClient) getEndpoint(translationType string) (string, error) {
 	switch translationType {
 	case consts.YodaTranslationType:
@@ -0,0 +1,37 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/metrics"
+)
+
+var _ Client = &InstrumentedTranslationClient{} // check if it implements the Client interface.
+
+// InstrumentedTranslationClient represents a client that records the count and latency of the requests
+// to a translation API, by translation type and outcome.
+type InstrumentedTranslationClient struct {
+	client  Client
+	metrics *metrics.Metrics
+}
+
+// NewInstrumentedTranslationClient returns a new TranslationClient recording the requests to the given metrics.
+func NewInstrumentedTranslationClient(client Client, m *metrics.Metrics) *InstrumentedTranslationClient {
+	return &InstrumentedTranslationClient{
+		client:  client,
+		metrics: m,
+	}
+}
+
+// Translate returns a translated text according to the translation type.
+func (c *InstrumentedTranslationClient) Translate(ctx context.Context, text, translationType string) (string, error) {
+	start := time.Now()
+	translation, err := c.client.Translate(ctx, text, translationType)
+	c.metrics.ObserveUpstream("translator", "translate_"+translationType, err, time.Since(start))
+	if err != nil {
+		return "", fmt.Errorf("failed to get translation: %w", err)
+	}
+	return translation, nil
+}
@@ -0,0 +1,2 @@
+// Package transport provides the configuration of the HTTP clients used to reach the upstream APIs.
+package transport
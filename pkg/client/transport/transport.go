@@ -0,0 +1,138 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/fra98/pokedex/pkg/client/retry"
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// DefaultTimeout is the timeout of the upstream requests when not configured.
+const DefaultTimeout = 10 * time.Second
+
+// Config contains the configuration of the HTTP client of an upstream.
+type Config struct {
+	// Timeout is the time limit of the requests, including retries.
+	Timeout time.Duration
+	// MaxIdleConns is the maximum number of idle (keep-alive) connections.
+	MaxIdleConns int
+	// IdleConnTimeout is the time an idle connection is kept open.
+	IdleConnTimeout time.Duration
+	// KeepAlive is the interval between keep-alive probes of the active connections.
+	KeepAlive time.Duration
+	// RootCAs is the set of root certificate authorities trusted by the client (nil means the system ones).
+	RootCAs *x509.CertPool
+	// ProxyURL is the URL of the HTTP proxy (nil means the one configured by the environment, if any).
+	ProxyURL *url.URL
+	// UserAgent is the User-Agent header of the requests.
+	UserAgent string
+	// Retry is the retry policy of the failed requests (nil disables retries).
+	Retry *retry.Policy
+}
+
+// NewClient returns a new HTTP client according to the configuration. If cfg is nil, defaults are used.
+func NewClient(cfg *Config) *http.Client {
+	if cfg == nil {
+		return &http.Client{Timeout: DefaultTimeout, Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: cfg.KeepAlive,
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != nil {
+		proxy = http.ProxyURL(cfg.ProxyURL)
+	}
+
+	var rt http.RoundTripper = &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConns,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig: &tls.Config{
+			RootCAs:    cfg.RootCAs,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+	// Trace every attempt, propagating the trace context to the upstream
+	rt = otelhttp.NewTransport(rt)
+	if cfg.Retry != nil {
+		rt = retry.NewTransport(rt, cfg.Retry)
+	}
+	if cfg.UserAgent != "" {
+		rt = &userAgentTransport{base: rt, userAgent: cfg.UserAgent}
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &http.Client{Timeout: timeout, Transport: rt}
+}
+
+// LoadCertPool returns the system certificate pool extended with the PEM certificates of the given file.
+func LoadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile) //nolint:gosec // the file is provided by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no valid certificate found in CA bundle %q: %w", caFile, errors.ErrInvalidConfiguration)
+	}
+	return pool, nil
+}
+
+// userAgentTransport is an http.RoundTripper setting the User-Agent header of the requests.
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req) //nolint:wrapcheck // the transport must return the errors as they are
+}
+
+// Ping checks whether the upstream at the given URL is reachable, sending a GET request with the given client.
+// Any response below 500 proves the upstream reachable, regardless of the resource.
+func Ping(ctx context.Context, client *http.Client, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("upstream unavailable (code: %d): %w", resp.StatusCode, errors.ErrFailedRequest)
+	}
+	return nil
+}
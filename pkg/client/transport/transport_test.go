@@ -0,0 +1,122 @@
+package transport_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/client/transport"
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+func get(t *testing.T, client *http.Client, target string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, target, http.NoBody)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestNewClient_UserAgent(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "pokedex-test", r.Header.Get("User-Agent"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp := get(t, transport.NewClient(&transport.Config{UserAgent: "pokedex-test"}), server.URL)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestNewClient_CustomCA(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// The self-signed certificate of the test server is not trusted by default
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+	resp, err := transport.NewClient(&transport.Config{}).Do(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	require.Error(t, err)
+
+	// Trusting the certificate through the CA bundle
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0o600))
+
+	rootCAs, err := transport.LoadCertPool(caFile)
+	require.NoError(t, err)
+
+	resp = get(t, transport.NewClient(&transport.Config{RootCAs: rootCAs}), server.URL)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestLoadCertPool_Invalid(t *testing.T) {
+	t.Parallel()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+	_, err := transport.LoadCertPool(caFile)
+	require.ErrorIs(t, err, errors.ErrInvalidConfiguration)
+
+	_, err = transport.LoadCertPool(filepath.Join(t.TempDir(), "missing.pem"))
+	require.Error(t, err)
+}
+
+func TestNewClient_Proxy(t *testing.T) {
+	t.Parallel()
+
+	// The proxy receives the requests with the absolute URL of the upstream
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "http://pokeapi.invalid/api/v2/pokemon-species/mewtwo", r.URL.String())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	resp := get(t, transport.NewClient(&transport.Config{ProxyURL: proxyURL}), "http://pokeapi.invalid/api/v2/pokemon-species/mewtwo")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestPing(t *testing.T) {
+	t.Parallel()
+
+	var statusCode atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(int(statusCode.Load()))
+	}))
+	defer server.Close()
+
+	client := transport.NewClient(nil)
+
+	// Any response below 500 proves the upstream reachable
+	statusCode.Store(http.StatusNotFound)
+	require.NoError(t, transport.Ping(t.Context(), client, server.URL))
+
+	statusCode.Store(http.StatusServiceUnavailable)
+	require.ErrorIs(t, transport.Ping(t.Context(), client, server.URL), errors.ErrFailedRequest)
+}
@@ -9,4 +9,13 @@ const (
 
 	// HabitatCaveType represents the cave habitat type.
 	HabitatCaveType = "cave"
+
+	// FallbackReasonRateLimited represents a translation fallback due to the translation API rate limit.
+	FallbackReasonRateLimited = "rate_limited"
+	// FallbackReasonCircuitOpen represents a translation fallback due to the translation API circuit breaker being open.
+	FallbackReasonCircuitOpen = "circuit_open"
+	// FallbackReasonBusy represents a translation fallback due to the translation API concurrency limit.
+	FallbackReasonBusy = "busy"
+	// FallbackReasonFailed represents a translation fallback due to any other translation failure.
+	FallbackReasonFailed = "failed"
 )
@@ -1,7 +1,13 @@
 // Package errors provides common error types for the application.
 package errors
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
 
 // ErrFailedRequest represents an error when a request fails.
 var ErrFailedRequest = errors.New("unexpected status code")
@@ -14,3 +20,119 @@ var ErrUnsupportedTranslationType = errors.New("unsupported translation type")
 
 // ErrResourceNotFound represents an error when a resource is not found.
 var ErrResourceNotFound = errors.New("resource not found")
+
+// ErrDescriptionNotFound represents an error when a Pokemon has no description in the requested language.
+var ErrDescriptionNotFound = errors.New("description not found")
+
+// ErrInvalidResponse represents an error when the response of an upstream API can not be decoded.
+var ErrInvalidResponse = errors.New("invalid upstream response")
+
+// ErrUpstreamTimeout represents an error when a request to an upstream API times out.
+var ErrUpstreamTimeout = errors.New("upstream request timed out")
+
+// ErrInvalidConfiguration represents an error when the provided configuration is not valid.
+var ErrInvalidConfiguration = errors.New("invalid configuration")
+
+// ErrNotReady represents an error when a component is not ready yet.
+var ErrNotReady = errors.New("not ready")
+
+// ErrCircuitOpen represents an error when a request is rejected since the circuit breaker of the upstream is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// ErrBulkheadFull represents an error when a request is rejected since the upstream concurrency limit is reached
+// and the wait queue is full (or the wait timed out).
+var ErrBulkheadFull = errors.New("too many concurrent requests")
+
+// ErrInvalidAPIKey represents an error when the API key of a request is missing, unknown or disabled.
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// ErrInvalidToken represents an error when the bearer token of a request is not a valid JWT.
+var ErrInvalidToken = errors.New("invalid token")
+
+// ErrInsufficientScope represents an error when the API key of a request lacks the scope required by the route.
+var ErrInsufficientScope = errors.New("insufficient scope")
+
+// ErrQuotaExceeded represents an error when the daily quota of an API key is exhausted.
+var ErrQuotaExceeded = errors.New("daily quota exceeded")
+
+// ErrUnsupportedFormat represents an error when a response can not be rendered in the negotiated format.
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// ErrInvalidCursor represents an error when a pagination cursor is malformed or does not match the query.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrIncompleteCatalog represents an error when too many species can not be retrieved to build the catalog.
+var ErrIncompleteCatalog = errors.New("incomplete catalog")
+
+// CircuitOpenError represents an error when a request is rejected by an open circuit breaker.
+// It wraps ErrCircuitOpen and carries the time after which the upstream can be tried again.
+type CircuitOpenError struct {
+	Upstream   string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: %v (retry after %s)", e.Upstream, ErrCircuitOpen, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap returns ErrCircuitOpen.
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// QuotaExceededError represents an error when a request is rejected since the daily quota of its API key is exhausted.
+// It wraps ErrQuotaExceeded and carries the time after which the quota is reset.
+type QuotaExceededError struct {
+	Key        string
+	Quota      int
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s: %v (%d requests, reset in %s)", e.Key, ErrQuotaExceeded, e.Quota, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap returns ErrQuotaExceeded.
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// UpstreamError represents a failed request to an upstream API.
+// It wraps the cause of the failure, e.g., ErrResourceNotFound, ErrRateLimitExceeded, ErrFailedRequest,
+// ErrInvalidResponse or the transport error, and it matches ErrUpstreamTimeout if the request timed out.
+type UpstreamError struct {
+	// Upstream is the name of the upstream API.
+	Upstream string
+	// StatusCode is the status code of the upstream response, or 0 if no response was received.
+	StatusCode int
+	// Err is the cause of the failure.
+	Err error
+}
+
+// NewUpstreamError returns a new UpstreamError for the given upstream, response status code (0 if none) and cause.
+func NewUpstreamError(upstream string, statusCode int, err error) *UpstreamError {
+	return &UpstreamError{Upstream: upstream, StatusCode: statusCode, Err: err}
+}
+
+func (e *UpstreamError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s (code: %d): %v", e.Upstream, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Upstream, e.Err)
+}
+
+// Unwrap returns the cause of the failure.
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches ErrUpstreamTimeout, when the request timed out.
+func (e *UpstreamError) Is(target error) bool {
+	return errors.Is(target, ErrUpstreamTimeout) && e.timeout()
+}
+
+// timeout returns whether the request timed out, either because of the context deadline or of the transport.
+func (e *UpstreamError) timeout() bool {
+	var netErr net.Error
+	return errors.Is(e.Err, context.DeadlineExceeded) || (errors.As(e.Err, &netErr) && netErr.Timeout())
+}
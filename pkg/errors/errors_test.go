@@ -0,0 +1,57 @@
+package errors_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+func TestNewUpstreamError(t *testing.T) {
+	t.Parallel()
+
+	dialTimeout := &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+
+	testCases := map[string]struct {
+		statusCode int
+		cause      error
+		timeout    bool
+	}{
+		"deadline_exceeded": {cause: context.DeadlineExceeded, timeout: true},
+		"dial_timeout":      {cause: dialTimeout, timeout: true},
+		"connection_error":  {cause: syscall.ECONNREFUSED},
+		"canceled":          {cause: context.Canceled},
+		"not_found":         {statusCode: http.StatusNotFound, cause: errors.ErrResourceNotFound},
+		"rate_limited":      {statusCode: http.StatusTooManyRequests, cause: errors.ErrRateLimitExceeded},
+		"unavailable":       {statusCode: http.StatusServiceUnavailable, cause: errors.ErrFailedRequest},
+		"server_error":      {statusCode: http.StatusInternalServerError, cause: errors.ErrFailedRequest},
+		"invalid_response":  {statusCode: http.StatusOK, cause: fmt.Errorf("%w: unexpected EOF", errors.ErrInvalidResponse)},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := fmt.Errorf("failed to get pokemon species: %w", errors.NewUpstreamError("pokeapi", tc.statusCode, tc.cause))
+
+			var upstreamErr *errors.UpstreamError
+			require.ErrorAs(t, err, &upstreamErr)
+			assert.Equal(t, "pokeapi", upstreamErr.Upstream)
+			assert.Equal(t, tc.statusCode, upstreamErr.StatusCode)
+			require.ErrorIs(t, err, tc.cause)
+			if tc.timeout {
+				require.ErrorIs(t, err, errors.ErrUpstreamTimeout)
+			} else {
+				require.NotErrorIs(t, err, errors.ErrUpstreamTimeout)
+			}
+		})
+	}
+}
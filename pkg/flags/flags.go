@@ -2,11 +2,16 @@
 package flags
 
 import (
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/spf13/pflag"
 )
 
+// CommandWarm is the command prefetching all the Pokemon species and exiting, instead of starting the server.
+const CommandWarm = "warm"
+
 // Init initializes flags to configure the server.
 func Init() *Options {
 	opts := NewOptions()
@@ -15,11 +20,148 @@ func Init() *Options {
 	pflag.DurationVar(&opts.ReadTimeout, "read-timeout", 10*time.Second, "Read timeout for the server")
 	pflag.DurationVar(&opts.WriteTimeout, "write-timeout", 10*time.Second, "Write timeout for the server")
 	pflag.DurationVar(&opts.ShutdownTimeout, "shutdown-timeout", 10*time.Second, "Graceful shutdown timeout for the server")
+	pflag.StringSliceVar(&opts.TrustedProxies, "trusted-proxies", nil,
+		"IPs or CIDRs of the reverse proxies whose X-Forwarded-For header is trusted to get the client IP")
+	pflag.StringSliceVar(&opts.CORSAllowedOrigins, "cors-allowed-origins", nil,
+		"Origins allowed to send cross-origin requests, exact, with a wildcard subdomain (e.g., https://*.example.com) or * (disabled if unset)")
+	pflag.StringSliceVar(&opts.CORSAllowedMethods, "cors-allowed-methods", []string{"GET", "HEAD", "POST"},
+		"Methods allowed in the cross-origin requests")
+	pflag.StringSliceVar(&opts.CORSAllowedHeaders, "cors-allowed-headers",
+		[]string{"Authorization", "X-API-Key", "If-None-Match", "X-Request-ID"},
+		"Request headers allowed in the cross-origin requests")
+	pflag.BoolVar(&opts.CORSAllowCredentials, "cors-allow-credentials", false,
+		"Allow the cross-origin requests carrying credentials (cookies, Authorization header)")
+	pflag.DurationVar(&opts.CORSMaxAge, "cors-max-age", 10*time.Minute, "Time the preflight responses can be cached by the browsers")
+	pflag.BoolVar(&opts.DisableCompression, "disable-compression", false, "Disable the compression of the response bodies (zstd, brotli, gzip)")
+	pflag.IntVar(&opts.CompressionMinSize, "compression-min-size", 1024, "Minimum size in bytes of the response bodies to compress")
+	pflag.StringSliceVar(&opts.CompressionExcludedTypes, "compression-excluded-types", []string{"image/", "video/", "audio/", "application/zip"},
+		"Content types never compressed, matched by prefix (e.g., already compressed formats)")
+	pflag.Float64Var(&opts.RateLimitRate, "rate-limit-rate", 10,
+		"Requests per second allowed to each client on the Pokemon endpoints (0 disables the limit)")
+	pflag.IntVar(&opts.RateLimitBurst, "rate-limit-burst", 20, "Maximum burst of requests allowed to each client on the Pokemon endpoints")
+	pflag.Float64Var(&opts.RateLimitTranslatedRate, "rate-limit-translated-rate", 1,
+		"Requests per second allowed to each client on the translated Pokemon endpoints (0 disables the limit)")
+	pflag.IntVar(&opts.RateLimitTranslatedBurst, "rate-limit-translated-burst", 5,
+		"Maximum burst of requests allowed to each client on the translated Pokemon endpoints")
+	pflag.DurationVar(&opts.PokeAPITimeout, "pokeapi-timeout", 10*time.Second, "Timeout of the PokeAPI requests, including retries")
+	pflag.DurationVar(&opts.TranslatorTimeout, "translator-timeout", 10*time.Second, "Timeout of the FunTranslations requests, including retries")
+	pflag.IntVar(&opts.UpstreamMaxIdleConns, "upstream-max-idle-conns", 100, "Maximum number of idle (keep-alive) connections to each upstream")
+	pflag.DurationVar(&opts.UpstreamIdleConnTimeout, "upstream-idle-conn-timeout", 90*time.Second,
+		"Time an idle connection to an upstream is kept open")
+	pflag.DurationVar(&opts.UpstreamKeepAlive, "upstream-keep-alive", 30*time.Second,
+		"Interval between keep-alive probes of the active connections to the upstreams (negative disables keep-alive probes)")
+	pflag.StringVar(&opts.UpstreamCAFile, "upstream-ca-file", "",
+		"PEM bundle of additional certificate authorities trusted when connecting to the upstreams (e.g., egress proxy CA)")
+	pflag.StringVar(&opts.UpstreamProxyURL, "upstream-proxy-url", "",
+		"URL of the HTTP proxy used to reach the upstreams (default from HTTP_PROXY/HTTPS_PROXY environment variables)")
+	pflag.StringVar(&opts.UpstreamUserAgent, "upstream-user-agent", "pokedex", "User-Agent header of the upstream requests")
+	pflag.IntVar(&opts.RetryMaxAttempts, "retry-max-attempts", 3, "Maximum number of attempts of the upstream requests, including the first one")
+	pflag.DurationVar(&opts.RetryBackoffBase, "retry-backoff-base", 100*time.Millisecond,
+		"Delay before the first retry of the upstream requests, doubled at each subsequent retry")
+	pflag.DurationVar(&opts.RetryBackoffCap, "retry-backoff-cap", 2*time.Second, "Maximum delay between two retries of the upstream requests")
+	pflag.Float64Var(&opts.RetryJitter, "retry-jitter", 0.5, "Fraction of the retry delay randomly subtracted from it, between 0 and 1")
+	pflag.IntSliceVar(&opts.RetryStatusCodes, "retry-status-codes", []int{429, 502, 503, 504},
+		"Upstream response status codes triggering a retry (404 and translation 429 are never retried)")
+	pflag.BoolVar(&opts.RetryNetworkErrors, "retry-network-errors", true, "Retry the upstream requests failed due to network errors")
+	pflag.BoolVar(&opts.DisableCircuitBreaker, "disable-circuit-breaker", false, "Disable the circuit breakers of the upstream clients")
+	pflag.IntVar(&opts.BreakerFailureThreshold, "circuit-breaker-failure-threshold", 5,
+		"Number of consecutive upstream failures opening the circuit breaker")
+	pflag.DurationVar(&opts.BreakerOpenTimeout, "circuit-breaker-open-timeout", 30*time.Second,
+		"Time the circuit breaker stays open before letting probe requests through")
+	pflag.IntVar(&opts.BreakerHalfOpenRequests, "circuit-breaker-half-open-requests", 1,
+		"Number of successful probe requests closing the circuit breaker")
+	pflag.DurationVar(&opts.ReadinessProbeInterval, "readiness-probe-interval", 30*time.Second,
+		"Minimum interval between the active probes of the upstreams run by the readiness endpoint, whose results are cached")
+	pflag.DurationVar(&opts.ReadinessProbeTimeout, "readiness-probe-timeout", 2*time.Second, "Timeout of the active probes of the upstreams")
+	pflag.IntVar(&opts.PokeAPIMaxConcurrency, "pokeapi-max-concurrency", 50, "Maximum number of concurrent PokeAPI requests (0 means unlimited)")
+	pflag.IntVar(&opts.PokeAPIMaxQueue, "pokeapi-max-queue", 100,
+		"Maximum number of PokeAPI requests waiting for the concurrency limit, exceeding ones are rejected")
+	pflag.IntVar(&opts.TranslatorMaxConcurrency, "translator-max-concurrency", 10,
+		"Maximum number of concurrent FunTranslations requests (0 means unlimited)")
+	pflag.IntVar(&opts.TranslatorMaxQueue, "translator-max-queue", 20,
+		"Maximum number of FunTranslations requests waiting for the concurrency limit, exceeding ones are rejected")
+	pflag.DurationVar(&opts.UpstreamQueueTimeout, "upstream-queue-timeout", time.Second,
+		"Maximum time an upstream request waits for the concurrency limit before being rejected")
+	pflag.BoolVar(&opts.HedgeRequests, "hedge-requests", false,
+		"Fire a second identical PokeAPI request when the first one is slower than the hedging delay, taking the first result")
+	pflag.Float64Var(&opts.HedgePercentile, "hedge-percentile", 0.95, "Percentile of the observed PokeAPI latencies used as hedging delay")
+	pflag.DurationVar(&opts.HedgeMinDelay, "hedge-min-delay", 50*time.Millisecond, "Minimum hedging delay")
+	pflag.DurationVar(&opts.HedgeMaxDelay, "hedge-max-delay", 250*time.Millisecond,
+		"Maximum hedging delay, also used until enough latencies are observed")
+	pflag.Float64Var(&opts.HedgeMaxPerSecond, "hedge-max-per-second", 10, "Maximum number of hedged PokeAPI requests per second")
+	pflag.BoolVar(&opts.DisableMetrics, "disable-metrics", false, "Disable the Prometheus metrics and the /metrics endpoint")
+	pflag.StringVar(&opts.LogLevel, "log-level", "info", "Minimum level of the logged records (debug, info, warn, error)")
+	pflag.StringVar(&opts.LogFormat, "log-format", "json", "Format of the logged records (json, text)")
+	pflag.StringVar(&opts.TracingExporter, "tracing-exporter", "none", "Exporter of the OpenTelemetry spans (none, otlp, stdout)")
+	pflag.StringVar(&opts.TracingOTLPEndpoint, "tracing-otlp-endpoint", "",
+		"Host:port of the OTLP/HTTP collector (default from OTEL_EXPORTER_OTLP_ENDPOINT, or localhost:4318)")
+	pflag.BoolVar(&opts.TracingOTLPInsecure, "tracing-otlp-insecure", false, "Disable TLS towards the OTLP collector")
+	pflag.Float64Var(&opts.TracingSampleRatio, "tracing-sample-ratio", 1, "Fraction of the traces started by the server which are sampled")
 	pflag.BoolVar(&opts.DisableCache, "disable-cache", false, "Disable caching")
 	pflag.DurationVar(&opts.CacheTimeoutExpiration, "cache-timeout-expiration", 1*time.Hour, "Cache timeout expiration")
 	pflag.DurationVar(&opts.CacheCleanupInterval, "cache-cleanup-interval", 24*time.Hour, "Cache cleanup interval")
+	pflag.DurationVar(&opts.CacheRevalidationWindow, "cache-revalidation-window", 24*time.Hour,
+		"Period during which expired PokeAPI entries are kept to be revalidated with conditional requests (0 disables revalidation)")
+	pflag.IntVar(&opts.CacheMaxEntries, "cache-max-entries", 10000, "Maximum number of entries of each cache (0 means unlimited)")
+	pflag.Int64Var(&opts.CacheMaxBytes, "cache-max-bytes", 64<<20, "Maximum estimated memory footprint of each cache, in bytes (0 means unlimited)")
+	pflag.StringVar(&opts.CacheEvictionPolicy, "cache-eviction-policy", "lru", "Eviction policy of the bounded caches (lru, lfu)")
+
+	pflag.DurationVar(&opts.HTTPCacheMaxAge, "http-cache-max-age", 0,
+		"Max-age of the cacheable API responses (0 means equal to the cache timeout expiration)")
+	pflag.DurationVar(&opts.HTTPCacheDegradedMaxAge, "http-cache-degraded-max-age", 1*time.Minute,
+		"Max-age of the translated API responses that fell back to the original description")
+	pflag.BoolVar(&opts.WarmCache, "warm-cache", false, "Prefetch all the Pokemon species into the cache at startup")
+	pflag.IntVar(&opts.WarmCacheConcurrency, "warm-cache-concurrency", 4, "Maximum number of concurrent requests during the cache warm-up")
+	pflag.DurationVar(&opts.WarmCacheInterval, "warm-cache-interval", 50*time.Millisecond,
+		"Minimum interval between two consecutive requests during the cache warm-up")
+	pflag.Float64Var(&opts.WarmCacheReadyThreshold, "warm-cache-ready-threshold", 0.9,
+		"Fraction of species to warm up before reporting ready, between 0 and 1")
+	pflag.BoolVar(&opts.DisableSuggestions, "disable-suggestions", false,
+		"Disable the suggestions of the closest species names in the responses to the Pokemon not found")
+	pflag.BoolVar(&opts.DisableSearch, "disable-search", false, "Disable the search index and the search endpoint it serves")
+	pflag.BoolVar(&opts.EnableCatalog, "enable-catalog", false,
+		"Enable the Pokemon catalog and the listing endpoint it serves, retrieving all the species from the PokeAPI when built")
+	pflag.DurationVar(&opts.CatalogRefreshInterval, "catalog-refresh-interval", 24*time.Hour,
+		"Interval between two builds of the Pokemon catalog (0 means built once at startup)")
+	pflag.IntVar(&opts.CatalogConcurrency, "catalog-concurrency", 4, "Maximum number of concurrent requests while building the Pokemon catalog")
+	pflag.DurationVar(&opts.CatalogInterval, "catalog-interval", 50*time.Millisecond,
+		"Minimum interval between two consecutive requests while building the Pokemon catalog")
+	pflag.StringVar(&opts.CatalogFile, "catalog-file", "",
+		"JSON file of the local snapshot of the Pokemon catalog, loaded at startup and saved after each build (disabled if unset)")
+	pflag.StringVar(&opts.APIKeysFile, "api-keys-file", "",
+		"JSON file of the API keys authorizing the requests, with their scopes and daily quotas (env: POKEDEX_API_KEYS, with the JSON content)")
+	pflag.BoolVar(&opts.AllowAnonymous, "allow-anonymous", false,
+		"Allow the requests without API key on the Pokemon endpoints, rate limited by IP address (always allowed if no API key is configured)")
+	pflag.StringVar(&opts.JWKSURL, "jwks-url", "",
+		"URL of the JWKS verifying the JWTs issued by the identity provider (JWT authentication is disabled if unset)")
+	pflag.StringVar(&opts.JWKSFile, "jwks-file", "", "JSON file of the JWKS verifying the JWTs, alternative to --jwks-url")
+	pflag.DurationVar(&opts.JWKSRefreshInterval, "jwks-refresh-interval", 1*time.Hour,
+		"Interval after which the JWKS is loaded again (0 disables the refresh)")
+	pflag.DurationVar(&opts.JWKSMinRefreshInterval, "jwks-min-refresh-interval", 1*time.Minute,
+		"Minimum interval between two JWKS loads, either periodic or triggered by tokens signed with unknown keys")
+	pflag.StringVar(&opts.JWTIssuer, "jwt-issuer", "", "Expected issuer (iss claim) of the JWTs, required with the JWKS")
+	pflag.StringVar(&opts.JWTAudience, "jwt-audience", "", "Expected audience (aud claim) of the JWTs, required with the JWKS")
+	pflag.StringVar(&opts.JWTScopeClaim, "jwt-scope-claim", "scope", "Claim of the JWTs carrying the granted scopes")
+	pflag.StringVar(&opts.JWTScopePrefix, "jwt-scope-prefix", "",
+		"Prefix of the scopes granted by the identity provider (e.g., pokedex:), stripped before matching")
+	pflag.DurationVar(&opts.JWTLeeway, "jwt-leeway", 30*time.Second, "Tolerated clock skew when validating the time-based claims of the JWTs")
+	pflag.StringVar(&opts.AdminToken, "admin-token", "",
+		"Bearer token authorizing the administration endpoints, in addition to the API keys with the admin scope (env: POKEDEX_ADMIN_TOKEN)")
+
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [warm] [flags]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Commands:\n  warm    Prefetch all the Pokemon species and exit, instead of starting the server\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n%s", pflag.CommandLine.FlagUsages())
+	}
 
 	pflag.Parse()
+	opts.Command = pflag.Arg(0)
+
+	// Secrets are read from the environment after parsing, to avoid printing them as flag defaults
+	if opts.AdminToken == "" {
+		opts.AdminToken = os.Getenv("POKEDEX_ADMIN_TOKEN")
+	}
+	opts.APIKeys = os.Getenv("POKEDEX_API_KEYS")
 
 	return opts
 }
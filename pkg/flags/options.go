@@ -11,13 +11,114 @@ func NewOptions() *Options {
 
 // Options contains the server options.
 type Options struct {
+	// Command to execute (empty to start the server)
+	Command string
 	// Server options
 	Address         string
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
+	TrustedProxies  []string
+	// CORS options
+	CORSAllowedOrigins   []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+	// Compression options
+	DisableCompression       bool
+	CompressionMinSize       int
+	CompressionExcludedTypes []string
+	// Rate limiting options
+	RateLimitRate            float64
+	RateLimitBurst           int
+	RateLimitTranslatedRate  float64
+	RateLimitTranslatedBurst int
+	// Upstream transport options
+	PokeAPITimeout          time.Duration
+	TranslatorTimeout       time.Duration
+	UpstreamMaxIdleConns    int
+	UpstreamIdleConnTimeout time.Duration
+	UpstreamKeepAlive       time.Duration
+	UpstreamCAFile          string
+	UpstreamProxyURL        string
+	UpstreamUserAgent       string
+	// Upstream retry options
+	RetryMaxAttempts   int
+	RetryBackoffBase   time.Duration
+	RetryBackoffCap    time.Duration
+	RetryJitter        float64
+	RetryStatusCodes   []int
+	RetryNetworkErrors bool
+	// Circuit breaker options
+	DisableCircuitBreaker   bool
+	BreakerFailureThreshold int
+	BreakerOpenTimeout      time.Duration
+	BreakerHalfOpenRequests int
+	// Readiness options
+	ReadinessProbeInterval time.Duration
+	ReadinessProbeTimeout  time.Duration
+	// Upstream concurrency limiting options
+	PokeAPIMaxConcurrency    int
+	PokeAPIMaxQueue          int
+	TranslatorMaxConcurrency int
+	TranslatorMaxQueue       int
+	UpstreamQueueTimeout     time.Duration
+	// Hedged requests options
+	HedgeRequests     bool
+	HedgePercentile   float64
+	HedgeMinDelay     time.Duration
+	HedgeMaxDelay     time.Duration
+	HedgeMaxPerSecond float64
+	// Metrics options
+	DisableMetrics bool
+	// Logging options
+	LogLevel  string
+	LogFormat string
+	// Tracing options
+	TracingExporter     string
+	TracingOTLPEndpoint string
+	TracingOTLPInsecure bool
+	TracingSampleRatio  float64
 	// Cache options
-	DisableCache           bool
-	CacheTimeoutExpiration time.Duration
-	CacheCleanupInterval   time.Duration
+	DisableCache            bool
+	CacheTimeoutExpiration  time.Duration
+	CacheCleanupInterval    time.Duration
+	CacheRevalidationWindow time.Duration
+	CacheMaxEntries         int
+	CacheMaxBytes           int64
+	CacheEvictionPolicy     string
+	// HTTP caching options
+	HTTPCacheMaxAge         time.Duration
+	HTTPCacheDegradedMaxAge time.Duration
+	// Cache warm-up options
+	WarmCache               bool
+	WarmCacheConcurrency    int
+	WarmCacheInterval       time.Duration
+	WarmCacheReadyThreshold float64
+	// Search options
+	DisableSuggestions bool
+	DisableSearch      bool
+	// Catalog options
+	EnableCatalog          bool
+	CatalogRefreshInterval time.Duration
+	CatalogConcurrency     int
+	CatalogInterval        time.Duration
+	CatalogFile            string
+	// Authentication options
+	APIKeysFile    string
+	APIKeys        string
+	AllowAnonymous bool
+	// JWT authentication options
+	JWKSURL                string
+	JWKSFile               string
+	JWKSRefreshInterval    time.Duration
+	JWKSMinRefreshInterval time.Duration
+	JWTIssuer              string
+	JWTAudience            string
+	JWTScopeClaim          string
+	JWTScopePrefix         string
+	JWTLeeway              time.Duration
+	// Administration options
+	AdminToken string
 }
@@ -0,0 +1,68 @@
+package health
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+var _ Detailer = &CachedChecker{} // check if it implements the Detailer interface.
+
+// CachedChecker is a Checker caching the result of an active probe, so that frequent readiness requests
+// do not flood the probed dependency.
+type CachedChecker struct {
+	checker Checker
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu        sync.Mutex
+	err       error
+	checkedAt time.Time
+	latency   time.Duration
+}
+
+// NewCachedChecker returns a new CachedChecker running the given probe at most once every ttl,
+// canceling it after timeout.
+func NewCachedChecker(checker Checker, ttl, timeout time.Duration) *CachedChecker {
+	return &CachedChecker{
+		checker: checker,
+		ttl:     ttl,
+		timeout: timeout,
+	}
+}
+
+// Check returns the cached result of the probe, running it again if expired.
+// Concurrent callers wait for the same probe, instead of running their own.
+func (c *CachedChecker) Check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checkedAt.IsZero() && time.Since(c.checkedAt) < c.ttl {
+		return c.err
+	}
+
+	// The probe outlives the request triggering it, since its result is shared
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	c.err = c.checker.Check(ctx)
+	c.checkedAt = time.Now()
+	c.latency = c.checkedAt.Sub(start)
+	return c.err
+}
+
+// Details returns the time and latency of the last probe, along with the details of the probed checker, if any.
+func (c *CachedChecker) Details() map[string]any {
+	details := make(map[string]any)
+	if detailer, ok := c.checker.(Detailer); ok {
+		maps.Copy(details, detailer.Details())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	details["checkedAt"] = c.checkedAt.UTC().Format(time.RFC3339)
+	details["latencyMs"] = c.latency.Milliseconds()
+	return details
+}
@@ -0,0 +1,2 @@
+// Package health provides the readiness checks of the application.
+package health
@@ -0,0 +1,123 @@
+package health
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// StatusOK represents a passing check.
+	StatusOK = "ok"
+	// StatusDegraded represents a failing non-critical check, which does not affect the readiness.
+	StatusDegraded = "degraded"
+	// StatusFail represents a failing critical check.
+	StatusFail = "fail"
+)
+
+// Checker is an interface that defines the method to check whether a component is ready.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc is an adapter to allow the use of ordinary functions as Checker.
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx).
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Detailer is an optional interface of the checkers reporting details about the checked component,
+// included in the check results.
+type Detailer interface {
+	Details() map[string]any
+}
+
+// Report represents the result of the readiness checks.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// CheckResult represents the result of a single readiness check.
+type CheckResult struct {
+	Status   string         `json:"status"`
+	Critical bool           `json:"critical"`
+	Error    string         `json:"error,omitempty"`
+	Details  map[string]any `json:"details,omitempty"`
+}
+
+type check struct {
+	checker  Checker
+	critical bool
+}
+
+// Registry collects the readiness checks of the application.
+type Registry struct {
+	mu     sync.RWMutex
+	checks map[string]check
+}
+
+// NewRegistry returns a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{checks: make(map[string]check)}
+}
+
+// Register adds a named check to the registry, replacing any check with the same name.
+// A failing critical check makes the application not ready, while a failing non-critical one makes it degraded.
+func (r *Registry) Register(name string, checker Checker, critical bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check{checker: checker, critical: critical}
+}
+
+// Check runs all the registered checks concurrently. The report status is fail if any critical check fails,
+// degraded if any non-critical check fails, ok otherwise.
+func (r *Registry) Check(ctx context.Context) *Report {
+	r.mu.RLock()
+	checks := make(map[string]check, len(r.checks))
+	for name, c := range r.checks {
+		checks[name] = c
+	}
+	r.mu.RUnlock()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	report := &Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checks))}
+	for name, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := runCheck(ctx, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = res
+			switch {
+			case res.Status == StatusFail:
+				report.Status = StatusFail
+			case res.Status == StatusDegraded && report.Status == StatusOK:
+				report.Status = StatusDegraded
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+func runCheck(ctx context.Context, c check) CheckResult {
+	res := CheckResult{Status: StatusOK, Critical: c.critical}
+	if err := c.checker.Check(ctx); err != nil {
+		res.Error = err.Error()
+		res.Status = StatusDegraded
+		if c.critical {
+			res.Status = StatusFail
+		}
+	}
+	if detailer, ok := c.checker.(Detailer); ok {
+		res.Details = detailer.Details()
+	}
+	return res
+}
@@ -0,0 +1,95 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/health"
+)
+
+var errUnreachable = errors.New("unreachable")
+
+func passing(context.Context) error { return nil }
+
+func failing(context.Context) error { return errUnreachable }
+
+func TestRegistry_Check(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		critical, nonCritical health.CheckerFunc
+		expectedStatus        string
+	}{
+		"all_passing":          {critical: passing, nonCritical: passing, expectedStatus: health.StatusOK},
+		"non_critical_failing": {critical: passing, nonCritical: failing, expectedStatus: health.StatusDegraded},
+		"critical_failing":     {critical: failing, nonCritical: passing, expectedStatus: health.StatusFail},
+		"all_failing":          {critical: failing, nonCritical: failing, expectedStatus: health.StatusFail},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := health.NewRegistry()
+			registry.Register("pokeapi", tc.critical, true)
+			registry.Register("translator", tc.nonCritical, false)
+
+			report := registry.Check(t.Context())
+			assert.Equal(t, tc.expectedStatus, report.Status)
+			require.Len(t, report.Checks, 2)
+			assert.True(t, report.Checks["pokeapi"].Critical)
+			assert.False(t, report.Checks["translator"].Critical)
+		})
+	}
+}
+
+func TestCachedChecker(t *testing.T) {
+	t.Parallel()
+
+	var probes atomic.Int64
+	probe := health.CheckerFunc(func(context.Context) error {
+		if probes.Add(1) == 1 {
+			return errUnreachable
+		}
+		return nil
+	})
+
+	ttl := 20 * time.Millisecond
+	checker := health.NewCachedChecker(probe, ttl, time.Second)
+
+	// The probe result is cached until expired
+	require.ErrorIs(t, checker.Check(t.Context()), errUnreachable)
+	require.ErrorIs(t, checker.Check(t.Context()), errUnreachable)
+	assert.Equal(t, int64(1), probes.Load())
+
+	time.Sleep(2 * ttl)
+	require.NoError(t, checker.Check(t.Context()))
+	assert.Equal(t, int64(2), probes.Load())
+
+	details := checker.Details()
+	assert.Contains(t, details, "checkedAt")
+	assert.Contains(t, details, "latencyMs")
+
+	// Registered checkers report their details
+	registry := health.NewRegistry()
+	registry.Register("pokeapi", checker, true)
+	assert.Contains(t, registry.Check(t.Context()).Checks["pokeapi"].Details, "checkedAt")
+}
+
+func TestCachedChecker_Timeout(t *testing.T) {
+	t.Parallel()
+
+	probe := health.CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	checker := health.NewCachedChecker(probe, time.Minute, 10*time.Millisecond)
+	require.ErrorIs(t, checker.Check(t.Context()), context.DeadlineExceeded)
+}
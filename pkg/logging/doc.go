@@ -0,0 +1,3 @@
+// Package logging provides the structured logging setup of the application, based on log/slog,
+// enriching the log records with the request ID and the trace context carried by the context.
+package logging
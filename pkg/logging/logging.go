@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// Formats of the log records.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Config contains the configuration of the logging.
+type Config struct {
+	// Level is the minimum level of the logged records (debug, info, warn, error).
+	Level string
+	// Format is the format of the log records (json, text).
+	Format string
+}
+
+// New returns a new logger writing to w according to the configuration.
+func New(w io.Writer, cfg *Config) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", cfg.Level, errors.ErrInvalidConfiguration)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q: %w", cfg.Format, errors.ErrInvalidConfiguration)
+	}
+	return slog.New(&contextHandler{Handler: handler}), nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of the context carrying the request ID, added to the records logged with it.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by the context, or an empty string if none.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHandler is a slog.Handler adding the request ID and the trace context carried by the context to the records.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("requestId", requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(slog.String("traceId", spanContext.TraceID().String()), slog.String("spanId", spanContext.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record) //nolint:wrapcheck // the handler must return the errors as they are
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
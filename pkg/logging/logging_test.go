@@ -0,0 +1,41 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/logging"
+)
+
+func TestNew_RequestID(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, &logging.Config{Level: "info", Format: logging.FormatJSON})
+	require.NoError(t, err)
+
+	ctx := logging.WithRequestID(t.Context(), "req-123")
+	logger.InfoContext(ctx, "Invalid cache entry", "key", "translation:yoda:text")
+	logger.DebugContext(ctx, "Below the configured level")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "Invalid cache entry", record["msg"])
+	assert.Equal(t, "req-123", record["requestId"])
+	assert.Equal(t, "translation:yoda:text", record["key"])
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := logging.New(&bytes.Buffer{}, &logging.Config{Level: "verbose", Format: logging.FormatJSON})
+	require.ErrorIs(t, err, errors.ErrInvalidConfiguration)
+
+	_, err = logging.New(&bytes.Buffer{}, &logging.Config{Level: "info", Format: "xml"})
+	require.ErrorIs(t, err, errors.ErrInvalidConfiguration)
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"maps"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/client/breaker"
+	"github.com/fra98/pokedex/pkg/client/bulkhead"
+	"github.com/fra98/pokedex/pkg/client/hedge"
+)
+
+// RegisterCache registers the statistics of the named cache, read at each scrape.
+func (m *Metrics) RegisterCache(name string, c cache.Cache) {
+	labels := prometheus.Labels{"cache": name}
+	m.MustRegister(
+		counterFunc("cache_hits_total", "Number of cache hits.", labels, func() float64 { return float64(c.Stats().Hits) }),
+		counterFunc("cache_misses_total", "Number of cache misses.", labels, func() float64 { return float64(c.Stats().Misses) }),
+		counterFunc("cache_evictions_total", "Number of entries evicted to honor the cache bounds.", labels,
+			func() float64 { return float64(c.Stats().Evictions) }),
+		gaugeFunc("cache_entries", "Number of entries stored in the cache.", labels, func() float64 { return float64(c.Stats().Entries) }),
+		gaugeFunc("cache_bytes", "Estimated memory footprint of the cache entries.", labels, func() float64 { return float64(c.Stats().Bytes) }),
+	)
+}
+
+// RegisterBreaker registers the state and the statistics of the circuit breaker.
+func (m *Metrics) RegisterBreaker(b *breaker.Breaker) {
+	labels := prometheus.Labels{"upstream": b.Name()}
+	m.MustRegister(
+		gaugeFunc("circuit_breaker_state", "State of the circuit breaker (0: closed, 1: open, 2: half-open).", labels,
+			func() float64 { return float64(b.State()) }),
+		counterFunc("circuit_breaker_rejected_total", "Number of requests rejected by the open circuit breaker.", labels,
+			func() float64 { return float64(b.Stats().Rejected) }),
+		counterFunc("circuit_breaker_openings_total", "Number of times the circuit breaker opened.", labels,
+			func() float64 { return float64(b.Stats().Openings) }),
+	)
+}
+
+const bulkheadRejectedHelp = "Number of upstream requests rejected by the bulkhead, by reason (queue_full, timeout)."
+
+// RegisterBulkhead registers the statistics of the bulkhead.
+func (m *Metrics) RegisterBulkhead(b *bulkhead.Bulkhead) {
+	labels := prometheus.Labels{"upstream": b.Name()}
+	m.MustRegister(
+		gaugeFunc("bulkhead_active_requests", "Number of upstream requests in flight through the bulkhead.", labels,
+			func() float64 { return float64(b.Stats().Active) }),
+		gaugeFunc("bulkhead_queued_requests", "Number of upstream requests waiting for the concurrency limit.", labels,
+			func() float64 { return float64(b.Stats().Queued) }),
+		counterFunc("bulkhead_rejected_total", bulkheadRejectedHelp, withLabel(labels, "reason", "queue_full"),
+			func() float64 { return float64(b.Stats().RejectedQueueFull) }),
+		counterFunc("bulkhead_rejected_total", bulkheadRejectedHelp, withLabel(labels, "reason", "timeout"),
+			func() float64 { return float64(b.Stats().RejectedTimeout) }),
+	)
+}
+
+// RegisterHedger registers the statistics of the hedged requests to the named upstream.
+func (m *Metrics) RegisterHedger(upstream string, h *hedge.Hedger) {
+	labels := prometheus.Labels{"upstream": upstream}
+	m.MustRegister(
+		gaugeFunc("hedge_delay_seconds", "Current delay after which a hedged request is fired.", labels,
+			func() float64 { return h.Stats().Delay.Seconds() }),
+		counterFunc("hedge_requests_total", "Number of hedged requests fired.", labels, func() float64 { return float64(h.Stats().Hedges) }),
+		counterFunc("hedge_won_total", "Number of hedged requests completed before the original ones.", labels,
+			func() float64 { return float64(h.Stats().Won) }),
+		counterFunc("hedge_throttled_total", "Number of hedged requests not fired because of the rate cap.", labels,
+			func() float64 { return float64(h.Stats().Throttled) }),
+	)
+}
+
+func counterFunc(name, help string, labels prometheus.Labels, fn func() float64) prometheus.CounterFunc {
+	return prometheus.NewCounterFunc(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help, ConstLabels: labels}, fn)
+}
+
+func gaugeFunc(name, help string, labels prometheus.Labels, fn func() float64) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help, ConstLabels: labels}, fn)
+}
+
+// withLabel returns a copy of the labels with the additional one.
+func withLabel(labels prometheus.Labels, name, value string) prometheus.Labels {
+	res := maps.Clone(labels)
+	res[name] = value
+	return res
+}
@@ -0,0 +1,2 @@
+// Package metrics provides the Prometheus metrics of the application, exposed by the /metrics endpoint.
+package metrics
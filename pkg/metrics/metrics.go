@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+const namespace = "pokedex"
+
+// Outcomes of the upstream requests.
+const (
+	OutcomeSuccess     = "success"
+	OutcomeNotFound    = "not_found"
+	OutcomeRateLimited = "rate_limited"
+	OutcomeTimeout     = "timeout"
+	OutcomeCanceled    = "canceled"
+	OutcomeFailed      = "failed"
+)
+
+// Metrics holds the Prometheus metrics of the application, registered to a dedicated registry.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests         *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	requestsInFlight prometheus.Gauge
+
+	upstreamRequests        *prometheus.CounterVec
+	upstreamRequestDuration *prometheus.HistogramVec
+
+	translationFallbacks *prometheus.CounterVec
+}
+
+// New returns a new Metrics, along with the Go runtime and process metrics.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests served, by route, method and status code.",
+		}, []string{"route", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of the HTTP requests served, by route, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		requestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests being served.",
+		}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_requests_total",
+			Help:      "Number of requests to the upstream APIs, by client, operation and outcome.",
+		}, []string{"client", "operation", "outcome"}),
+		upstreamRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "Latency of the requests to the upstream APIs, by client, operation and outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"client", "operation", "outcome"}),
+		translationFallbacks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "translation_fallbacks_total",
+			Help:      "Number of translated responses falling back to the original description, by reason.",
+		}, []string{"reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.requestsInFlight,
+		m.upstreamRequests,
+		m.upstreamRequestDuration,
+		m.translationFallbacks,
+	)
+	return m
+}
+
+// Handler returns the HTTP handler exposing the metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// MustRegister registers additional collectors, panicking on failure (e.g., duplicated metrics).
+func (m *Metrics) MustRegister(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+// RequestStarted tracks an HTTP request being served, returning the function to call once served.
+func (m *Metrics) RequestStarted() (done func()) {
+	m.requestsInFlight.Inc()
+	return m.requestsInFlight.Dec
+}
+
+// ObserveRequest records a served HTTP request.
+func (m *Metrics) ObserveRequest(route, method string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.requests.WithLabelValues(route, method, code).Inc()
+	m.requestDuration.WithLabelValues(route, method, code).Observe(duration.Seconds())
+}
+
+// ObserveUpstream records a request to an upstream API, classifying its outcome from the returned error.
+func (m *Metrics) ObserveUpstream(client, operation string, err error, duration time.Duration) {
+	outcome := Outcome(err)
+	m.upstreamRequests.WithLabelValues(client, operation, outcome).Inc()
+	m.upstreamRequestDuration.WithLabelValues(client, operation, outcome).Observe(duration.Seconds())
+}
+
+// ObserveTranslationFallback records a translated response falling back to the original description.
+func (m *Metrics) ObserveTranslationFallback(reason string) {
+	m.translationFallbacks.WithLabelValues(reason).Inc()
+}
+
+// Outcome returns the outcome of an upstream request from its error.
+func Outcome(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case stderrors.Is(err, errors.ErrResourceNotFound):
+		return OutcomeNotFound
+	case stderrors.Is(err, errors.ErrRateLimitExceeded):
+		return OutcomeRateLimited
+	case stderrors.Is(err, errors.ErrUpstreamTimeout):
+		return OutcomeTimeout
+	case stderrors.Is(err, context.Canceled):
+		return OutcomeCanceled
+	default:
+		return OutcomeFailed
+	}
+}
@@ -0,0 +1,75 @@
+package metrics_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/cache"
+	apperrors "github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/metrics"
+)
+
+// scrape is a helper function returning the metrics exposed by the handler, in the text format.
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestOutcome(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err      error
+		expected string
+	}{
+		"success":      {err: nil, expected: metrics.OutcomeSuccess},
+		"not_found":    {err: fmt.Errorf("species: %w", apperrors.ErrResourceNotFound), expected: metrics.OutcomeNotFound},
+		"rate_limited": {err: fmt.Errorf("translate: %w", apperrors.ErrRateLimitExceeded), expected: metrics.OutcomeRateLimited},
+		"canceled":     {err: fmt.Errorf("send: %w", context.Canceled), expected: metrics.OutcomeCanceled},
+		"failed":       {err: fmt.Errorf("send: %w", apperrors.ErrFailedRequest), expected: metrics.OutcomeFailed},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, metrics.Outcome(tc.err))
+		})
+	}
+}
+
+func TestMetrics_Exposition(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+
+	m.ObserveRequest("/v1/pokemon/:name", http.MethodGet, http.StatusOK, 10*time.Millisecond)
+	m.ObserveUpstream("translator", "translate_yoda", nil, 100*time.Millisecond)
+	m.ObserveUpstream("translator", "translate_yoda", apperrors.ErrRateLimitExceeded, 100*time.Millisecond)
+	m.ObserveTranslationFallback("rate_limited")
+
+	c := cache.NewMemoryCache(time.Minute, time.Hour)
+	c.Set("key", "value", cache.DefaultExpiration)
+	c.Get("key")
+	c.Get("missing")
+	m.RegisterCache("translation", c)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `pokedex_http_requests_total{method="GET",route="/v1/pokemon/:name",status="200"} 1`)
+	assert.Contains(t, body, `pokedex_upstream_requests_total{client="translator",operation="translate_yoda",outcome="success"} 1`)
+	assert.Contains(t, body, `pokedex_upstream_requests_total{client="translator",operation="translate_yoda",outcome="rate_limited"} 1`)
+	assert.Contains(t, body, `pokedex_translation_fallbacks_total{reason="rate_limited"} 1`)
+	assert.Contains(t, body, `pokedex_cache_hits_total{cache="translation"} 1`)
+	assert.Contains(t, body, `pokedex_cache_misses_total{cache="translation"} 1`)
+	assert.Contains(t, body, `pokedex_http_requests_in_flight 0`)
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIKeysUsageResponse represents the usage of the API keys.
+type APIKeysUsageResponse struct {
+	XMLName xml.Name      `json:"-"    xml:"apiKeys" yaml:"-"`
+	Keys    []APIKeyUsage `json:"keys" xml:"key"     yaml:"keys"`
+}
+
+// Header returns the CSV columns of the usage of the API keys.
+func (r APIKeysUsageResponse) Header() []string {
+	return []string{"name", "scopes", "disabled", "dailyQuota", "usedToday", "rejectedToday", "totalRequests", "resetAt"}
+}
+
+// Records returns the CSV records of the usage of the API keys, with the scopes separated by spaces.
+func (r APIKeysUsageResponse) Records() [][]string {
+	records := make([][]string, 0, len(r.Keys))
+	for _, key := range r.Keys {
+		records = append(records, []string{
+			key.Name,
+			strings.Join(key.Scopes, " "),
+			strconv.FormatBool(key.Disabled),
+			strconv.Itoa(key.DailyQuota),
+			strconv.Itoa(key.UsedToday),
+			strconv.Itoa(key.RejectedToday),
+			strconv.FormatInt(key.TotalRequests, 10),
+			key.ResetAt.Format(time.RFC3339),
+		})
+	}
+	return records
+}
+
+// APIKeyUsage represents the usage of an API key, along with its daily quota.
+type APIKeyUsage struct {
+	Name          string    `json:"name"          xml:"name"          yaml:"name"`
+	Scopes        []string  `json:"scopes"        xml:"scopes>scope"  yaml:"scopes"`
+	Disabled      bool      `json:"disabled"      xml:"disabled"      yaml:"disabled"`
+	DailyQuota    int       `json:"dailyQuota"    xml:"dailyQuota"    yaml:"dailyQuota"`
+	UsedToday     int       `json:"usedToday"     xml:"usedToday"     yaml:"usedToday"`
+	RejectedToday int       `json:"rejectedToday" xml:"rejectedToday" yaml:"rejectedToday"`
+	TotalRequests int64     `json:"totalRequests" xml:"totalRequests" yaml:"totalRequests"`
+	ResetAt       time.Time `json:"resetAt"       xml:"resetAt"       yaml:"resetAt"`
+}
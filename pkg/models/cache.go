@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/cache"
+)
+
+// CacheStatsResponse represents the statistics of the configured caches, indexed by cache name.
+type CacheStatsResponse map[string]cache.Stats
+
+// CacheKeysResponse represents a list of cache keys.
+type CacheKeysResponse struct {
+	XMLName xml.Name   `json:"-"    xml:"cacheKeys" yaml:"-"`
+	Keys    []CacheKey `json:"keys" xml:"key"       yaml:"keys"`
+}
+
+// Header returns the CSV columns of the cache keys.
+func (r CacheKeysResponse) Header() []string {
+	return []string{"cache", "key"}
+}
+
+// Records returns the CSV records of the cache keys.
+func (r CacheKeysResponse) Records() [][]string {
+	records := make([][]string, 0, len(r.Keys))
+	for _, key := range r.Keys {
+		records = append(records, []string{key.Cache, key.Key})
+	}
+	return records
+}
+
+// CacheKey represents a key stored in a cache.
+type CacheKey struct {
+	Cache string `json:"cache" xml:"cache" yaml:"cache"`
+	Key   string `json:"key"   xml:"name"  yaml:"key"`
+}
+
+// CacheEntryResponse represents an entry stored in a cache.
+type CacheEntryResponse struct {
+	Cache     string     `json:"cache"`
+	Key       string     `json:"key"`
+	Value     any        `json:"value"`
+	Size      int64      `json:"size"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Age       string     `json:"age"`
+	TTL       string     `json:"ttl,omitempty"`
+}
+
+// CachePurgeResponse represents the result of a purge of cache keys.
+type CachePurgeResponse struct {
+	Deleted int `json:"deleted"`
+}
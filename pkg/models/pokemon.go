@@ -1,9 +1,53 @@
 package models
 
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
 // PokemonResponse represents a Pokemon response.
 type PokemonResponse struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Habitat     string `json:"habitat"`
-	IsLegendary bool   `json:"isLegendary"`
+	XMLName     xml.Name `json:"-"           xml:"pokemon"     yaml:"-"`
+	Name        string   `json:"name"        xml:"name"        yaml:"name"`
+	Description string   `json:"description" xml:"description" yaml:"description"`
+	Habitat     string   `json:"habitat"     xml:"habitat"     yaml:"habitat"`
+	IsLegendary bool     `json:"isLegendary" xml:"isLegendary" yaml:"isLegendary"`
+
+	// TranslationFallback is the reason why the translated description fell back to the original one, if it did.
+	TranslationFallback string `json:"-" xml:"-" yaml:"-"`
+}
+
+// PokemonListResponse represents a page of the Pokemon listing.
+type PokemonListResponse struct {
+	XMLName xml.Name         `json:"-"              xml:"pokemonList"    yaml:"-"`
+	Total   int              `json:"total"          xml:"total,attr"     yaml:"total"`
+	Next    string           `json:"next,omitempty" xml:"next,omitempty" yaml:"next,omitempty"`
+	Pokemon []PokemonSummary `json:"pokemon"        xml:"pokemon"        yaml:"pokemon"`
+}
+
+// Header returns the CSV columns of the listed Pokemon.
+func (r PokemonListResponse) Header() []string {
+	return []string{"id", "name", "generation", "habitat", "isLegendary", "types"}
+}
+
+// Records returns the CSV records of the listed Pokemon, with the types separated by spaces.
+func (r PokemonListResponse) Records() [][]string {
+	records := make([][]string, 0, len(r.Pokemon))
+	for _, p := range r.Pokemon {
+		records = append(records, []string{
+			strconv.Itoa(p.ID), p.Name, strconv.Itoa(p.Generation), p.Habitat, strconv.FormatBool(p.IsLegendary), strings.Join(p.Types, " "),
+		})
+	}
+	return records
+}
+
+// PokemonSummary represents a Pokemon of the listing.
+type PokemonSummary struct {
+	ID          int      `json:"id"          xml:"id"          yaml:"id"`
+	Name        string   `json:"name"        xml:"name"        yaml:"name"`
+	Generation  int      `json:"generation"  xml:"generation"  yaml:"generation"`
+	Habitat     string   `json:"habitat"     xml:"habitat"     yaml:"habitat"`
+	IsLegendary bool     `json:"isLegendary" xml:"isLegendary" yaml:"isLegendary"`
+	Types       []string `json:"types"       xml:"types>type"  yaml:"types"`
 }
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// SearchResponse represents the results of a search, with the best matches first.
+type SearchResponse struct {
+	XMLName xml.Name       `json:"-"       xml:"search"     yaml:"-"`
+	Query   string         `json:"query"   xml:"query,attr" yaml:"query"`
+	Total   int            `json:"total"   xml:"total,attr" yaml:"total"`
+	Results []SearchResult `json:"results" xml:"result"     yaml:"results"`
+}
+
+// Header returns the CSV columns of the search results.
+func (r SearchResponse) Header() []string {
+	return []string{"name", "score", "nameMatch", "highlight", "snippet"}
+}
+
+// Records returns the CSV records of the search results.
+func (r SearchResponse) Records() [][]string {
+	records := make([][]string, 0, len(r.Results))
+	for _, res := range r.Results {
+		records = append(records, []string{res.Name, strconv.FormatFloat(res.Score, 'f', 3, 64), res.NameMatch, res.Highlight, res.Snippet})
+	}
+	return records
+}
+
+// SearchResult represents a Pokemon matching a search, with the matching parts of its name and description highlighted.
+type SearchResult struct {
+	Name      string  `json:"name"                xml:"name"                yaml:"name"`
+	Score     float64 `json:"score"               xml:"score"               yaml:"score"`
+	NameMatch string  `json:"nameMatch,omitempty" xml:"nameMatch,omitempty" yaml:"nameMatch,omitempty"`
+	Highlight string  `json:"highlight,omitempty" xml:"highlight,omitempty" yaml:"highlight,omitempty"`
+	Snippet   string  `json:"snippet,omitempty"   xml:"snippet,omitempty"   yaml:"snippet,omitempty"`
+}
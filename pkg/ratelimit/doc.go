@@ -0,0 +1,3 @@
+// Package ratelimit provides token bucket rate limiting of the API clients,
+// with the buckets kept in a pluggable store.
+package ratelimit
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Store = &MemoryStore{} // check if it implements the Store interface.
+
+// sweepInterval is the minimum interval between two removals of the full buckets.
+const sweepInterval = time.Minute
+
+// MemoryStore is a Store keeping the token buckets in memory, local to the server instance.
+// The full buckets are periodically removed, since they are equivalent to missing ones.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*limitedBucket
+	lastSweep time.Time
+}
+
+// limitedBucket is a bucket along with its limit, needed to tell whether it is full.
+type limitedBucket struct {
+	bucket
+	limit Limit
+}
+
+// NewMemoryStore returns a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*limitedBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Take takes a token from the bucket of the key, creating it full if missing.
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, found := s.buckets[key]
+	if !found {
+		b = &limitedBucket{bucket: bucket{tokens: float64(limit.Burst), updated: now}}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	return b.take(limit, now), nil
+}
+
+// Len returns the number of buckets stored.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
+
+// sweep removes the full buckets. It must be called with the lock held.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if b.full(b.limit, now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,73 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/ratelimit"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	t.Parallel()
+
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Rate: 1, Burst: 2}
+
+	// The bucket starts full, allowing a burst of requests
+	res, err := store.Take(t.Context(), "client", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Limit)
+	assert.Equal(t, 1, res.Remaining)
+
+	res, err = store.Take(t.Context(), "client", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.InDelta(t, 2*time.Second, res.Reset, float64(50*time.Millisecond))
+
+	// The empty bucket rejects the requests until a token is added
+	res, err = store.Take(t.Context(), "client", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.InDelta(t, time.Second, res.RetryAfter, float64(50*time.Millisecond))
+
+	// The buckets are independent of each other
+	res, err = store.Take(t.Context(), "other", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, store.Len())
+}
+
+func TestMemoryStore_Refill(t *testing.T) {
+	t.Parallel()
+
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Rate: 100, Burst: 1}
+
+	res, err := store.Take(t.Context(), "client", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	res, err = store.Take(t.Context(), "client", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+
+	// A token is added every 10ms
+	time.Sleep(20 * time.Millisecond)
+	res, err = store.Take(t.Context(), "client", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestLimit_Enabled(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, ratelimit.Limit{Rate: 1, Burst: 1}.Enabled())
+	assert.False(t, ratelimit.Limit{Rate: 0, Burst: 1}.Enabled())
+	assert.False(t, ratelimit.Limit{Rate: 1, Burst: 0}.Enabled())
+}
@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit is the rate limit of a token bucket.
+type Limit struct {
+	// Rate is the number of tokens added to the bucket per second.
+	Rate float64
+	// Burst is the capacity of the bucket, i.e., the maximum number of requests allowed at once.
+	Burst int
+}
+
+// Enabled returns whether the limit is enforced.
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	// Allowed tells whether a token was available, i.e., the request is allowed.
+	Allowed bool
+	// Limit is the capacity of the bucket.
+	Limit int
+	// Remaining is the number of tokens left in the bucket.
+	Remaining int
+	// Reset is the time after which the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time after which a token is available, if the request is not allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps the token buckets, indexed by key.
+// Implementations backed by a shared database allow enforcing the limits across multiple instances.
+type Store interface {
+	// Take takes a token from the bucket of the key, creating it full if missing.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// bucket is the state of a token bucket.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills the bucket according to the time elapsed since the last update, and takes a token if available.
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	burst := float64(limit.Burst)
+	b.tokens = min(burst, b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+
+	res := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / limit.Rate)
+	}
+	res.Remaining = int(math.Floor(b.tokens))
+	res.Reset = seconds((burst - b.tokens) / limit.Rate)
+	return res
+}
+
+// full returns whether the bucket would be full at the given time.
+func (b *bucket) full(limit Limit, now time.Time) bool {
+	return b.tokens+now.Sub(b.updated).Seconds()*limit.Rate >= float64(limit.Burst)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
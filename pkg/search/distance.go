@@ -0,0 +1,34 @@
+package search
+
+// Distance returns the edit distance between two strings, i.e., the minimum number of insertions, deletions,
+// substitutions and transpositions of adjacent characters turning one into the other (optimal string alignment).
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return max(len(ra), len(rb))
+	}
+
+	// Rows of the distance matrix: the one before the previous, the previous and the current one
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
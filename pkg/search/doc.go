@@ -0,0 +1,3 @@
+// Package search provides the full-text and fuzzy search of the Pokemon species, through an in-memory inverted index
+// of their names and English descriptions, updated as the species are retrieved from the PokeAPI.
+package search
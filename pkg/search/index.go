@@ -0,0 +1,279 @@
+package search
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+)
+
+var _ pokeapi.SpeciesObserver = &Index{} // check if it implements the SpeciesObserver interface.
+
+// BM25 parameters of the description ranking.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Scores of the name matches, weighted above the description ones, since searching by name is the most common case.
+const (
+	scoreExact       = 20.0
+	scorePrefix      = 10.0
+	scoreFuzzy       = 8.0
+	scoreFuzzyPrefix = 4.0
+	// scoreTypoPenalty is subtracted from the fuzzy matches for each typo.
+	scoreTypoPenalty = 2.0
+	// fuzzyTermWeight is the weight of the description terms matching a query term with a typo.
+	fuzzyTermWeight = 0.5
+)
+
+// NameMatch is how the name of a species matches the query.
+type NameMatch string
+
+// Name matches, from the best to the worst.
+const (
+	MatchExact       NameMatch = "exact"
+	MatchPrefix      NameMatch = "prefix"
+	MatchFuzzy       NameMatch = "fuzzy"
+	MatchFuzzyPrefix NameMatch = "fuzzy-prefix"
+)
+
+// Hit is a species matching a query.
+type Hit struct {
+	Name  string
+	Score float64
+	// NameMatch is how the name matches the query, empty if it does not.
+	NameMatch NameMatch
+	// Highlight is the name with the matching part highlighted, empty if it does not match.
+	Highlight string
+	// Snippet is the excerpt of the description with the matching terms highlighted, empty if it does not match.
+	Snippet string
+}
+
+// Result is the result of a query, with the best hits first.
+type Result struct {
+	Hits []Hit
+	// Total is the number of species matching the query, including the ones beyond the limit.
+	Total int
+}
+
+// document is an indexed species.
+type document struct {
+	name   string
+	text   string
+	tokens []token
+	// terms are the frequencies of the terms of the text.
+	terms map[string]int
+}
+
+// Index is an in-memory inverted index of the names and English descriptions of the species. It is safe for concurrent use.
+// The species are indexed as they are observed, so that the index grows incrementally as the species are retrieved from the PokeAPI.
+type Index struct {
+	mu sync.RWMutex
+	// docs are the indexed species, by name.
+	docs map[string]*document
+	// postings are the frequencies of each term, by species name.
+	postings map[string]map[string]int
+	// totalTerms is the number of terms of all the texts, to compute their average length.
+	totalTerms int
+}
+
+// NewIndex returns a new empty Index.
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string]int),
+	}
+}
+
+// ObserveSpecies indexes the species, replacing its previous version if its description changed.
+func (idx *Index) ObserveSpecies(species *pokeapi.PokemonSpecies) {
+	idx.Add(species.Name, englishFlavorTexts(species))
+}
+
+// Add indexes the species with the given name and flavor texts, replacing its previous version if the texts changed.
+func (idx *Index) Add(name string, flavorTexts []string) {
+	text := englishText(flavorTexts)
+
+	idx.mu.RLock()
+	existing, found := idx.docs[name]
+	idx.mu.RUnlock()
+	if found && existing.text == text {
+		return
+	}
+
+	doc := &document{name: name, text: text, tokens: tokenize(text), terms: make(map[string]int)}
+	for _, t := range doc.tokens {
+		doc.terms[t.term]++
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if old, found := idx.docs[name]; found {
+		for term := range old.terms {
+			delete(idx.postings[term], name)
+			if len(idx.postings[term]) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+		idx.totalTerms -= len(old.tokens)
+	}
+	for term, freq := range doc.terms {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[string]int)
+		}
+		idx.postings[term][name] = freq
+	}
+	idx.totalTerms += len(doc.tokens)
+	idx.docs[name] = doc
+}
+
+// Len returns the number of indexed species.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Search returns up to limit species matching the query, either by name or by description, with the best hits first.
+// The names match exactly, by prefix (for autocompletion) or with typos, while the descriptions are ranked by BM25.
+func (idx *Index) Search(query string, limit int) *Result {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	hits := make(map[string]*Hit)
+	hit := func(name string) *Hit {
+		if hits[name] == nil {
+			hits[name] = &Hit{Name: name}
+		}
+		return hits[name]
+	}
+
+	// Name matches, with the words of the query joined as in the PokeAPI names (e.g., "mr mime" for "mr-mime")
+	nameQuery := strings.Join(strings.Fields(normalize(query)), "-")
+	for name := range idx.docs {
+		if match, score, highlighted := matchName(nameQuery, name); match != "" {
+			h := hit(name)
+			h.NameMatch, h.Score, h.Highlight = match, score, highlightName(name, highlighted)
+		}
+	}
+
+	// Description matches, each query term also matching the terms with a typo
+	matches := make(map[string]map[string]bool)
+	avgLength := float64(idx.totalTerms) / float64(max(len(idx.docs), 1))
+	for _, t := range tokenize(query) {
+		for term, weight := range idx.expand(t.term) {
+			idf := math.Log(1 + (float64(len(idx.docs))-float64(len(idx.postings[term]))+0.5)/(float64(len(idx.postings[term]))+0.5))
+			for name, freq := range idx.postings[term] {
+				length := float64(len(idx.docs[name].tokens))
+				tf := float64(freq) * (bm25K1 + 1) / (float64(freq) + bm25K1*(1-bm25B+bm25B*length/avgLength))
+				hit(name).Score += weight * idf * tf
+				if matches[name] == nil {
+					matches[name] = make(map[string]bool)
+				}
+				matches[name][term] = true
+			}
+		}
+	}
+	for name, terms := range matches {
+		doc := idx.docs[name]
+		hits[name].Snippet = snippet(doc.text, doc.tokens, terms)
+	}
+
+	res := &Result{Hits: make([]Hit, 0, len(hits)), Total: len(hits)}
+	for _, h := range hits {
+		res.Hits = append(res.Hits, *h)
+	}
+	slices.SortFunc(res.Hits, func(a, b Hit) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Name, b.Name))
+	})
+	if len(res.Hits) > limit {
+		res.Hits = res.Hits[:limit]
+	}
+	return res
+}
+
+// expand returns the indexed terms matching the query term, along with their weight: the term itself if indexed,
+// otherwise the terms differing by a single typo, if the term is long enough to tolerate typos.
+func (idx *Index) expand(term string) map[string]float64 {
+	if _, found := idx.postings[term]; found {
+		return map[string]float64{term: 1}
+	}
+	if maxTypos(term) == 0 {
+		return nil
+	}
+
+	expanded := make(map[string]float64)
+	for indexed := range idx.postings {
+		if abs(utf8.RuneCountInString(indexed)-utf8.RuneCountInString(term)) <= 1 && Distance(term, indexed) <= 1 {
+			expanded[indexed] = fuzzyTermWeight
+		}
+	}
+	return expanded
+}
+
+// matchName returns how the name matches the query, the score of the match and the number of runes to highlight
+// (0 for the whole name), or an empty match if it does not.
+func matchName(query, name string) (NameMatch, float64, int) {
+	if query == "" {
+		return "", 0, 0
+	}
+	if query == name {
+		return MatchExact, scoreExact, 0
+	}
+
+	queryLength, nameLength := utf8.RuneCountInString(query), utf8.RuneCountInString(name)
+	if strings.HasPrefix(name, query) {
+		// The longer the prefix, the closer the match
+		return MatchPrefix, scorePrefix * float64(queryLength) / float64(nameLength), queryLength
+	}
+
+	typos := maxTypos(query)
+	if typos == 0 {
+		return "", 0, 0
+	}
+	if d := Distance(query, name); d <= typos {
+		return MatchFuzzy, scoreFuzzy - scoreTypoPenalty*float64(d), 0
+	}
+	if queryLength < nameLength {
+		if d := Distance(query, string([]rune(name)[:queryLength])); d <= typos {
+			return MatchFuzzyPrefix, scoreFuzzyPrefix - scoreTypoPenalty*float64(d)/2, queryLength
+		}
+	}
+	return "", 0, 0
+}
+
+// maxTypos returns the number of typos tolerated in a term, according to its length: none up to 3 runes,
+// one up to 7 runes, and two for longer terms.
+func maxTypos(term string) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n <= 3:
+		return 0
+	case n <= 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// englishFlavorTexts returns the English flavor texts of the species.
+func englishFlavorTexts(species *pokeapi.PokemonSpecies) []string {
+	var texts []string
+	for i := range species.FlavorTextEntries {
+		if entry := &species.FlavorTextEntries[i]; entry.Language.Name == "en" {
+			texts = append(texts, entry.FlavorText)
+		}
+	}
+	return texts
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,147 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+	"github.com/fra98/pokedex/pkg/search"
+)
+
+// newIndex is a helper function to setup an index of a few species with their English flavor texts.
+func newIndex() *search.Index {
+	idx := search.NewIndex()
+	idx.Add("pikachu", []string{
+		"When several of\nthese POKéMON gather, their\felectricity could build and cause lightning storms.",
+		"When several of these POKéMON gather, their electricity could build and cause lightning storms.",
+	})
+	idx.Add("raichu", []string{"Its long tail serves as a ground to protect itself from its own high-voltage power. It evolves from Pikachu."})
+	idx.Add("pichu", []string{"It is not yet skilled at storing electricity. It may send out a jolt if amused or startled."})
+	idx.Add("mewtwo", []string{"It was created by a scientist after years of horrific gene splicing and DNA engineering experiments."})
+	idx.Add("mew", []string{"So rare that it is still said to be a mirage by many experts. Only a few people have seen it worldwide."})
+	idx.Add("mr-mime", []string{"If interrupted while it is miming, it will slap around the offender with its broad hands."})
+	return idx
+}
+
+func names(res *search.Result) []string {
+	names := make([]string, 0, len(res.Hits))
+	for _, h := range res.Hits {
+		names = append(names, h.Name)
+	}
+	return names
+}
+
+func TestIndex_SearchNames(t *testing.T) {
+	t.Parallel()
+
+	idx := newIndex()
+
+	testCases := map[string]struct {
+		query    string
+		expected []string
+		match    search.NameMatch
+	}{
+		"exact":        {query: "Pikachu", expected: []string{"pikachu", "raichu"}, match: search.MatchExact},
+		"prefix":       {query: "pi", expected: []string{"pichu", "pikachu"}, match: search.MatchPrefix},
+		"short_prefix": {query: "mew", expected: []string{"mew", "mewtwo"}, match: search.MatchExact},
+		"typo":         {query: "pikachuu", expected: []string{"pikachu", "raichu"}, match: search.MatchFuzzy},
+		"transposed":   {query: "mewtow", expected: []string{"mewtwo"}, match: search.MatchFuzzy},
+		"typo_prefix":  {query: "pikc", expected: []string{"pikachu"}, match: search.MatchFuzzyPrefix},
+		"words":        {query: "mr mime", expected: []string{"mr-mime"}, match: search.MatchExact},
+		"short_typo":   {query: "mow", expected: []string{}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res := idx.Search(tc.query, 10)
+			assert.Equal(t, tc.expected, names(res))
+			if len(tc.expected) > 0 {
+				assert.Equal(t, tc.match, res.Hits[0].NameMatch)
+			}
+		})
+	}
+}
+
+func TestIndex_SearchDescriptions(t *testing.T) {
+	t.Parallel()
+
+	idx := newIndex()
+
+	// The species with the most relevant descriptions come first
+	res := idx.Search("electricity storms", 10)
+	assert.Equal(t, []string{"pikachu", "pichu"}, names(res))
+	assert.Equal(t, 2, res.Total)
+	assert.Empty(t, res.Hits[0].NameMatch)
+	assert.Equal(t, "When several of these POKéMON gather, their <em>electricity</em> could build and cause lightning <em>storms</em>.",
+		res.Hits[0].Snippet)
+
+	// The terms are matched regardless of case and accents, and with a typo
+	res = idx.Search("pokemon", 10)
+	assert.Equal(t, []string{"pikachu"}, names(res))
+	res = idx.Search("scientst", 10)
+	assert.Equal(t, []string{"mewtwo"}, names(res))
+	assert.Contains(t, res.Hits[0].Snippet, "<em>scientist</em>")
+
+	// The snippets of long descriptions are truncated around the first match
+	res = idx.Search("offender", 10)
+	require.Len(t, res.Hits, 1)
+	assert.Equal(t, "…it is miming, it will slap around the <em>offender</em> with its broad hands.", res.Hits[0].Snippet)
+
+	// The hits are limited, but they are all counted
+	res = idx.Search("it", 2)
+	assert.Len(t, res.Hits, 2)
+	assert.Equal(t, 5, res.Total)
+}
+
+func TestIndex_Incremental(t *testing.T) {
+	t.Parallel()
+
+	idx := search.NewIndex()
+	assert.Empty(t, idx.Search("pikachu", 10).Hits)
+
+	// The observed species are indexed
+	idx.ObserveSpecies(&pokeapi.PokemonSpecies{Name: "pikachu", FlavorTextEntries: []pokeapi.FlavorTextEntry{
+		{FlavorText: "It stores electricity in its cheeks.", Language: pokeapi.Language{Name: "en"}},
+		{FlavorText: "Il stocke l'électricité dans ses joues.", Language: pokeapi.Language{Name: "fr"}},
+	}})
+	assert.Equal(t, 1, idx.Len())
+	assert.Equal(t, []string{"pikachu"}, names(idx.Search("cheeks", 10)))
+	assert.Empty(t, idx.Search("joues", 10).Hits)
+
+	// The species whose description changed are reindexed
+	idx.Add("pikachu", []string{"It raises its tail to check its surroundings."})
+	assert.Equal(t, 1, idx.Len())
+	assert.Empty(t, idx.Search("cheeks", 10).Hits)
+	assert.Equal(t, []string{"pikachu"}, names(idx.Search("surroundings", 10)))
+}
+
+func TestDistance(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		a, b     string
+		expected int
+	}{
+		"equal":         {a: "pikachu", b: "pikachu", expected: 0},
+		"empty":         {a: "", b: "mew", expected: 3},
+		"substitution":  {a: "pikachu", b: "pikachy", expected: 1},
+		"insertion":     {a: "pikachu", b: "pikkachu", expected: 1},
+		"deletion":      {a: "pikachu", b: "pikchu", expected: 1},
+		"transposition": {a: "mewtwo", b: "mewtow", expected: 1},
+		"unicode":       {a: "flabébé", b: "flabebe", expected: 2},
+		"different":     {a: "mew", b: "onix", expected: 4},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, search.Distance(tc.a, tc.b))
+			assert.Equal(t, tc.expected, search.Distance(tc.b, tc.a))
+		})
+	}
+}
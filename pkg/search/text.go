@@ -0,0 +1,119 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a term of a text, along with its byte offsets in the original text.
+type token struct {
+	term       string
+	start, end int
+}
+
+// folder folds the accented letters found in the PokeAPI texts (e.g., "Pokémon").
+var folder = strings.NewReplacer("é", "e", "É", "e", "♀", "-f", "♂", "-m")
+
+// normalize returns the lower case and accent-folded form of a term.
+func normalize(s string) string {
+	return folder.Replace(strings.ToLower(s))
+}
+
+// tokenize splits the text into its terms, i.e., the sequences of letters and digits.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			tokens = append(tokens, token{term: normalize(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: normalize(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// englishText returns the English flavor texts of a species, without the duplicates repeated across the game versions,
+// joined in a single text. The line breaks of the PokeAPI texts are replaced by spaces, and the soft hyphens removed.
+func englishText(entries []string) string {
+	seen := make(map[string]bool, len(entries))
+	texts := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		text := strings.Join(strings.Fields(strings.ReplaceAll(entry, "\u00ad", "")), " ")
+		if key := normalize(text); text != "" && !seen[key] {
+			seen[key] = true
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+// Context of the snippets, in terms before and after the first match.
+const (
+	snippetBefore = 8
+	snippetAfter  = 24
+)
+
+// Markers of the highlighted matches.
+const (
+	highlightStart = "<em>"
+	highlightEnd   = "</em>"
+)
+
+// snippet returns the excerpt of the text around its first term matching, with all the matching terms highlighted.
+// The text is HTML-escaped, so that the snippet can be rendered as HTML. Truncated ends are marked with an ellipsis.
+func snippet(text string, tokens []token, matches map[string]bool) string {
+	first := -1
+	for i, t := range tokens {
+		if matches[t.term] {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	from := max(first-snippetBefore, 0)
+	to := min(first+snippetAfter, len(tokens)-1)
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := tokens[from].start
+	for _, t := range tokens[from : to+1] {
+		if !matches[t.term] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:t.start]))
+		b.WriteString(highlightStart + html.EscapeString(text[t.start:t.end]) + highlightEnd)
+		pos = t.end
+	}
+	end := tokens[to].end
+	if to == len(tokens)-1 {
+		end = len(text)
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// highlightName returns the name with its first runes highlighted, the whole name if n is not positive.
+func highlightName(name string, n int) string {
+	if n <= 0 || n >= utf8.RuneCountInString(name) {
+		return highlightStart + html.EscapeString(name) + highlightEnd
+	}
+	split := len(string([]rune(name)[:n]))
+	return highlightStart + html.EscapeString(name[:split]) + highlightEnd + html.EscapeString(name[split:])
+}
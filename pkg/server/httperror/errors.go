@@ -1,19 +1,106 @@
 package httperror
 
-// HTTPError represents an HTTP error.
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ContentType is the media type of the error responses, as defined by RFC 9457.
+const ContentType = "application/problem+json"
+
+// StatusClientClosedRequest is the non-standard status code of the requests canceled by the client before the response,
+// as logged by nginx. The clients never receive it, but it tells them apart from the server failures in logs and metrics.
+const StatusClientClosedRequest = 499
+
+// typePrefix is the prefix of the URIs identifying the problem types, followed by the kebab-case error code.
+const typePrefix = "urn:pokedex:problem:"
+
+// Stable machine-readable error codes, part of the API contract.
+const (
+	CodePokemonNotFound     = "POKEMON_NOT_FOUND"
+	CodeDescriptionNotFound = "DESCRIPTION_NOT_FOUND"
+	CodeUpstreamRateLimited = "UPSTREAM_RATE_LIMITED"
+	CodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamBusy        = "UPSTREAM_BUSY"
+	CodeUpstreamTimeout     = "UPSTREAM_TIMEOUT"
+	CodeUpstreamFailed      = "UPSTREAM_FAILED"
+	CodeUpstreamBadResponse = "UPSTREAM_BAD_RESPONSE"
+	CodeClientClosedRequest = "CLIENT_CLOSED_REQUEST"
+	CodeRateLimited         = "RATE_LIMITED"
+	CodeCacheEntryNotFound  = "CACHE_ENTRY_NOT_FOUND"
+	CodeCatalogNotReady     = "CATALOG_NOT_READY"
+	CodeInvalidRequest      = "INVALID_REQUEST"
+	CodeNotAcceptable       = "NOT_ACCEPTABLE"
+	CodeUnauthorized        = "UNAUTHORIZED"
+	CodeForbidden           = "FORBIDDEN"
+	CodeQuotaExceeded       = "QUOTA_EXCEEDED"
+	CodeAPIKeyNotFound      = "API_KEY_NOT_FOUND"
+	CodeInternalError       = "INTERNAL_ERROR"
+)
+
+// titles holds the short summary of each problem type, which does not change from occurrence to occurrence.
+var titles = map[string]string{
+	CodePokemonNotFound:     "Pokemon not found",
+	CodeDescriptionNotFound: "Pokemon description not found",
+	CodeUpstreamRateLimited: "Upstream API rate limit exceeded",
+	CodeUpstreamUnavailable: "Upstream API unavailable",
+	CodeUpstreamBusy:        "Upstream API busy",
+	CodeUpstreamTimeout:     "Upstream API timeout",
+	CodeUpstreamFailed:      "Upstream API failure",
+	CodeUpstreamBadResponse: "Invalid upstream API response",
+	CodeClientClosedRequest: "Client closed request",
+	CodeRateLimited:         "Rate limit exceeded",
+	CodeCacheEntryNotFound:  "Cache entry not found",
+	CodeCatalogNotReady:     "Pokemon catalog not ready",
+	CodeInvalidRequest:      "Invalid request",
+	CodeNotAcceptable:       "Not acceptable",
+	CodeUnauthorized:        "Unauthorized",
+	CodeForbidden:           "Insufficient scope",
+	CodeQuotaExceeded:       "Daily quota exceeded",
+	CodeAPIKeyNotFound:      "API key not found",
+	CodeInternalError:       "Internal server error",
+}
+
+// HTTPError represents an HTTP error, serialized as RFC 9457 problem details.
 type HTTPError struct {
-	Message    string `json:"message,omitempty"`
-	StatusCode int    `json:"statusCode"`
+	// Type is the URI identifying the problem type.
+	Type string `json:"type"`
+	// Title is the human-readable summary of the problem type.
+	Title string `json:"title"`
+	// Status is the HTTP status code.
+	Status int `json:"status"`
+	// Detail is the human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Instance is the URI reference identifying this occurrence of the problem, i.e., the request path.
+	Instance string `json:"instance,omitempty"`
+	// Code is the stable machine-readable code of the problem type, to be used by clients in place of the detail.
+	Code string `json:"code"`
+	// RequestID is the ID of the failed request, to correlate the error with the server logs.
+	RequestID string `json:"requestId,omitempty"`
+	// Suggestions are the names of the resources close to the requested one, when it is not found.
+	Suggestions []string `json:"suggestions,omitempty"`
+
+	// RetryAfter is the delay after which the client can retry the request, sent as Retry-After header if set.
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (e HTTPError) Error() string {
-	return "message: " + e.Message
+	return e.Code + ": " + e.Detail
 }
 
-// NewHTTPError returns a new HTTP error.
-func NewHTTPError(message string, statusCode int) HTTPError {
+// NewHTTPError returns a new HTTP error with the given status code, error code and detail.
+func NewHTTPError(statusCode int, code, detail string) HTTPError {
+	title, found := titles[code]
+	if !found {
+		title = http.StatusText(statusCode)
+	}
+
 	return HTTPError{
-		Message:    message,
-		StatusCode: statusCode,
+		Type:   typePrefix + strings.ReplaceAll(strings.ToLower(code), "_", "-"),
+		Title:  title,
+		Status: statusCode,
+		Detail: detail,
+		Code:   code,
 	}
 }
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/auth"
+	apperrors "github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+)
+
+// APIKeyHeader is the header carrying the API key, as alternative to the bearer token in the Authorization header.
+const APIKeyHeader = "X-API-Key"
+
+// Auth is a middleware that authorizes only the requests carrying an API key or a JWT granted the given scope,
+// either as bearer token in the Authorization header or, for the API keys, in the X-API-Key header.
+// The requests are counted against the daily quota of the API key, and they are rate limited by client ID instead of by IP address.
+// If anonymous requests are allowed, the requests without credentials are let through.
+func Auth(authenticator *auth.Authenticator, scope auth.Scope, allowAnonymous bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		credential := credentials(c)
+		if credential == "" && allowAnonymous {
+			c.Next()
+			return
+		}
+
+		clientID, err := authenticator.Authorize(c.Request.Context(), credential, scope)
+		if err != nil {
+			_ = c.Error(authHTTPError(c, err))
+			c.Abort()
+			return
+		}
+
+		SetClientID(c, clientID)
+		c.Next()
+	}
+}
+
+// credentials returns the API key or the token carried by the request, if any.
+func credentials(c *gin.Context) string {
+	if token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); found {
+		return token
+	}
+	return c.GetHeader(APIKeyHeader)
+}
+
+// authHTTPError returns the HTTP error corresponding to the authorization error.
+func authHTTPError(c *gin.Context, err error) httperror.HTTPError {
+	var quotaErr *apperrors.QuotaExceededError
+	switch {
+	case errors.As(err, &quotaErr):
+		httpErr := httperror.NewHTTPError(http.StatusTooManyRequests, httperror.CodeQuotaExceeded, "the daily quota of the API key is exhausted")
+		httpErr.RetryAfter = quotaErr.RetryAfter
+		return httpErr
+	case errors.Is(err, apperrors.ErrInsufficientScope):
+		return httperror.NewHTTPError(http.StatusForbidden, httperror.CodeForbidden, "the credentials are not allowed to access this endpoint")
+	case errors.Is(err, apperrors.ErrInvalidToken):
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		return httperror.NewHTTPError(http.StatusUnauthorized, httperror.CodeUnauthorized, "invalid or expired token")
+	default:
+		c.Header("WWW-Authenticate", "Bearer")
+		return httperror.NewHTTPError(http.StatusUnauthorized, httperror.CodeUnauthorized, "invalid or missing API key")
+	}
+}
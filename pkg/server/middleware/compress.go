@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content encodings supported by the Compress middleware.
+const (
+	EncodingZstd   = "zstd"
+	EncodingBrotli = "br"
+	EncodingGzip   = "gzip"
+)
+
+// encodings are the supported content encodings, in order of preference when accepted with the same quality.
+var encodings = []string{EncodingZstd, EncodingBrotli, EncodingGzip}
+
+// CompressConfig contains the configuration of the Compress middleware.
+type CompressConfig struct {
+	// MinSize is the minimum size of the response bodies to compress, the smaller ones are sent as they are.
+	MinSize int
+	// ExcludedContentTypes are the media types never compressed, matched by prefix (e.g., "image/").
+	ExcludedContentTypes []string
+}
+
+// encoder compresses the response bodies with a content encoding, reusing its writers.
+type encoder struct {
+	pool sync.Pool
+}
+
+func (e *encoder) encode(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, _ := e.pool.Get().(resetWriter)
+	w.Reset(&buf)
+	defer e.pool.Put(w)
+
+	if _, err := w.Write(body); err != nil {
+		return nil, fmt.Errorf("failed to compress response: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress response: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// resetWriter is a compressing writer that can be reused for another output.
+type resetWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// encoders are the encoders of the supported content encodings, shared by the middlewares.
+var encoders = map[string]*encoder{
+	EncodingZstd: {pool: sync.Pool{New: func() any {
+		w, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		return w
+	}}},
+	EncodingBrotli: {pool: sync.Pool{New: func() any { return brotli.NewWriterLevel(nil, brotli.DefaultCompression) }}},
+	EncodingGzip:   {pool: sync.Pool{New: func() any { return gzip.NewWriter(nil) }}},
+}
+
+// compressWriter is a bufferedWriter that also defers sending the headers, so that they can be altered once the handlers completed.
+type compressWriter struct {
+	bufferedWriter
+}
+
+func (w *compressWriter) WriteHeaderNow() {}
+
+// Compress is a middleware that compresses the response bodies with the content encoding negotiated from the Accept-Encoding header,
+// among zstd, brotli and gzip. The bodies smaller than the minimum size, of the excluded content types or already encoded are sent as they are.
+// The responses vary on the Accept-Encoding header, and their strong ETags are weakened when the body is compressed,
+// since the compressed representation is not byte-for-byte identical to the original one, as well as on the 304 responses
+// when an encoding is negotiated, as mandated by RFC 9110.
+func Compress(cfg CompressConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{bufferedWriter{ResponseWriter: c.Writer}}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		header := c.Writer.Header()
+		body := writer.body.Bytes()
+		if writer.Status() == http.StatusNotModified {
+			// The 304 response stands for the compressed representation the client revalidated, as its 200 would be
+			weakenETag(header)
+		} else if compressible(cfg, header, writer.Status(), len(body)) {
+			compressed, err := encoders[encoding].encode(body)
+			if err != nil {
+				slog.WarnContext(c.Request.Context(), "Sending uncompressed response", "encoding", encoding, "error", err)
+			} else {
+				header.Set("Content-Encoding", encoding)
+				header.Del("Content-Length")
+				weakenETag(header)
+				body = compressed
+			}
+		}
+
+		// Without body, the headers are sent once the handlers completed, as if not buffered
+		if len(body) > 0 {
+			_, _ = c.Writer.Write(body)
+		}
+	}
+}
+
+// weakenETag weakens the ETag of the response, if strong.
+func weakenETag(header http.Header) {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+}
+
+// compressible returns whether the response body should be compressed.
+func compressible(cfg CompressConfig, header http.Header, status, size int) bool {
+	if size == 0 || size < cfg.MinSize || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(header.Get("Content-Type"), ";")[0]))
+	for _, excluded := range cfg.ExcludedContentTypes {
+		if strings.HasPrefix(mediaType, strings.ToLower(excluded)) {
+			return false
+		}
+	}
+	return true
+}
+
+// negotiateEncoding returns the supported content encoding with the highest quality in the Accept-Encoding header,
+// or an empty string if none is accepted. The wildcard applies to the encodings not explicitly listed.
+func negotiateEncoding(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	wildcard := -1.0
+	for item := range strings.SplitSeq(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(item, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if name == "*" {
+			wildcard = q
+		} else {
+			qualities[name] = q
+		}
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range encodings {
+		q, found := qualities[encoding]
+		if !found {
+			q = wildcard
+		}
+		if q > bestQuality {
+			best, bestQuality = encoding, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,159 @@
+package middleware_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/server/middleware"
+)
+
+var largeBody = strings.Repeat("Mewtwo was created by a scientist after years of horrific gene splicing. ", 50)
+
+// setupCompress is a helper function to setup a gin engine compressing the responses larger than 100 bytes, except images.
+// The /cached route sets the HTTP caching headers, as the Pokemon endpoints do.
+func setupCompress() *gin.Engine {
+	engine := gin.New()
+	engine.Use(middleware.Compress(middleware.CompressConfig{MinSize: 100, ExcludedContentTypes: []string{"image/"}}))
+	engine.GET("/large", func(c *gin.Context) {
+		c.String(http.StatusOK, largeBody)
+	})
+	engine.GET("/small", func(c *gin.Context) {
+		c.String(http.StatusOK, "Mewtwo")
+	})
+	engine.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(largeBody))
+	})
+	engine.GET("/cached", middleware.HTTPCache(middleware.HTTPCacheConfig{}), func(c *gin.Context) {
+		c.String(http.StatusOK, largeBody)
+	})
+	engine.GET("/cached-small", middleware.HTTPCache(middleware.HTTPCacheConfig{}), func(c *gin.Context) {
+		c.String(http.StatusOK, "Mewtwo")
+	})
+	return engine
+}
+
+// decode returns the response body decoded according to its content encoding.
+func decode(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+
+	var r io.Reader
+	switch encoding {
+	case middleware.EncodingGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		r = gr
+	case middleware.EncodingBrotli:
+		r = brotli.NewReader(bytes.NewReader(body))
+	case middleware.EncodingZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		defer zr.Close()
+		r = zr
+	default:
+		return string(body)
+	}
+	decoded, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(decoded)
+}
+
+func TestCompress_Negotiation(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCompress()
+
+	testCases := map[string]struct {
+		acceptEncoding string
+		expected       string
+	}{
+		"none":               {acceptEncoding: "", expected: ""},
+		"gzip":               {acceptEncoding: "gzip", expected: middleware.EncodingGzip},
+		"brotli":             {acceptEncoding: "br", expected: middleware.EncodingBrotli},
+		"zstd":               {acceptEncoding: "zstd", expected: middleware.EncodingZstd},
+		"browser":            {acceptEncoding: "gzip, deflate, br, zstd", expected: middleware.EncodingZstd},
+		"quality":            {acceptEncoding: "zstd;q=0.5, br;q=0.8, gzip;q=1.0", expected: middleware.EncodingGzip},
+		"rejected":           {acceptEncoding: "br;q=0, gzip;q=0.1", expected: middleware.EncodingGzip},
+		"wildcard":           {acceptEncoding: "*", expected: middleware.EncodingZstd},
+		"wildcard_excluding": {acceptEncoding: "zstd;q=0, *;q=0.5", expected: middleware.EncodingBrotli},
+		"unsupported":        {acceptEncoding: "deflate, identity", expected: ""},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serve(engine, "/large", map[string]string{"Accept-Encoding": tc.acceptEncoding})
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.expected, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			assert.Equal(t, largeBody, decode(t, tc.expected, w.Body.Bytes()))
+			if tc.expected != "" {
+				assert.Less(t, w.Body.Len(), len(largeBody))
+			}
+		})
+	}
+}
+
+func TestCompress_Skipped(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCompress()
+	headers := map[string]string{"Accept-Encoding": "gzip"}
+
+	// The bodies smaller than the minimum size are not compressed
+	w := serve(engine, "/small", headers)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Mewtwo", w.Body.String())
+
+	// The excluded content types are not compressed
+	w = serve(engine, "/image", headers)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, largeBody, w.Body.String())
+
+	// The unknown routes are answered as usual
+	w = serve(engine, "/missing", headers)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "404 page not found", w.Body.String())
+}
+
+func TestCompress_ETag(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCompress()
+
+	// The ETag is strong for the identity representation
+	w := serve(engine, "/cached", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.False(t, strings.HasPrefix(etag, "W/"))
+
+	// The ETag is weakened for the compressed representation
+	w = serve(engine, "/cached", map[string]string{"Accept-Encoding": "br"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, middleware.EncodingBrotli, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "W/"+etag, w.Header().Get("ETag"))
+
+	// The weak ETag revalidates the compressed representation, the bodiless response carrying the same weak ETag
+	w = serve(engine, "/cached", map[string]string{"Accept-Encoding": "br", "If-None-Match": "W/" + etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "W/"+etag, w.Header().Get("ETag"))
+
+	// The ETag is kept strong for the responses not compressed, even if an encoding is negotiated
+	w = serve(engine, "/cached-small", map[string]string{"Accept-Encoding": "br"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.False(t, strings.HasPrefix(w.Header().Get("ETag"), "W/"))
+}
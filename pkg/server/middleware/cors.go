@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// corsExposedHeaders are the response headers readable by the cross-origin clients, besides the CORS-safelisted ones.
+var corsExposedHeaders = []string{
+	RequestIDHeader, "ETag", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "WWW-Authenticate",
+}
+
+// CORSConfig contains the configuration of the CORS middleware.
+type CORSConfig struct {
+	// AllowedOrigins are the origins allowed to access the API: exact origins (e.g., https://pokedex.example.com),
+	// origins with a wildcard subdomain (e.g., https://*.example.com), or "*" for any origin. CORS is disabled if empty.
+	AllowedOrigins []string
+	// AllowedMethods are the methods allowed in the cross-origin requests.
+	AllowedMethods []string
+	// AllowedHeaders are the request headers allowed in the cross-origin requests, besides the CORS-safelisted ones.
+	AllowedHeaders []string
+	// AllowCredentials allows the cross-origin requests carrying credentials (cookies, Authorization header).
+	// It is not allowed along with "*", which would let any website send credentialed requests on behalf of the users.
+	AllowCredentials bool
+	// MaxAge is the time the preflight responses can be cached by the browsers (0 leaves the browser default).
+	MaxAge time.Duration
+}
+
+// Enabled returns whether cross-origin requests are allowed.
+func (cfg CORSConfig) Enabled() bool {
+	return len(cfg.AllowedOrigins) > 0
+}
+
+// Validate returns an error if the configuration allows the credentials from any origin.
+func (cfg CORSConfig) Validate() error {
+	if cfg.AllowCredentials && slices.Contains(cfg.AllowedOrigins, "*") {
+		return fmt.Errorf("CORS credentials can not be allowed from any origin: %w", errors.ErrInvalidConfiguration)
+	}
+	return nil
+}
+
+// originPattern matches an allowed origin, possibly with a wildcard subdomain.
+type originPattern struct {
+	prefix, suffix string
+	wildcard       bool
+}
+
+func newOriginPattern(origin string) originPattern {
+	origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+	if prefix, suffix, found := strings.Cut(origin, "*."); found {
+		return originPattern{prefix: prefix, suffix: "." + suffix, wildcard: true}
+	}
+	return originPattern{prefix: origin}
+}
+
+// match returns whether the origin (lowercase) matches the pattern. A wildcard matches one or more subdomain labels.
+func (p originPattern) match(origin string) bool {
+	if !p.wildcard {
+		return origin == p.prefix
+	}
+	if len(origin) <= len(p.prefix)+len(p.suffix) || !strings.HasPrefix(origin, p.prefix) || !strings.HasSuffix(origin, p.suffix) {
+		return false
+	}
+	subdomain := origin[len(p.prefix) : len(origin)-len(p.suffix)]
+	return !strings.ContainsAny(subdomain, "/:@?#") && !strings.HasPrefix(subdomain, ".")
+}
+
+// CORS is a middleware that allows the cross-origin requests from the configured origins, setting the CORS response headers.
+// The preflight requests are answered with 204 No Content before reaching the routes, hence bypassing authorization and rate limits.
+// The requests from the other origins are served without CORS headers, so that the browsers block their responses.
+// The credentials are never allowed from any origin, even if the configuration is not validated.
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	anyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+	credentials := cfg.AllowCredentials && !anyOrigin
+	patterns := make([]originPattern, 0, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		patterns = append(patterns, newOriginPattern(origin))
+	}
+	allowed := func(origin string) bool {
+		origin = strings.ToLower(origin)
+		return anyOrigin || slices.ContainsFunc(patterns, func(p originPattern) bool { return p.match(origin) })
+	}
+
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	exposed := strings.Join(corsExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		// The response depends on the origin, unless any origin is allowed
+		if !anyOrigin {
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if origin == "" {
+			c.Next()
+			return
+		}
+		if preflight {
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Method, Access-Control-Request-Headers")
+		}
+
+		if allowed(origin) {
+			if anyOrigin {
+				c.Header("Access-Control-Allow-Origin", "*")
+			} else {
+				c.Header("Access-Control-Allow-Origin", origin)
+			}
+			if credentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+
+			if preflight {
+				c.Header("Access-Control-Allow-Methods", methods)
+				if headers != "" {
+					c.Header("Access-Control-Allow-Headers", headers)
+				}
+				if cfg.MaxAge > 0 {
+					c.Header("Access-Control-Max-Age", maxAge)
+				}
+			} else {
+				c.Header("Access-Control-Expose-Headers", exposed)
+			}
+		}
+
+		if preflight {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
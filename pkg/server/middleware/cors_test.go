@@ -0,0 +1,135 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+)
+
+// setupCORS is a helper function to setup a gin engine with the CORS middleware and a route rejecting all the requests,
+// as an authorization middleware would do without credentials.
+func setupCORS(cfg middleware.CORSConfig) *gin.Engine {
+	engine := gin.New()
+	engine.Use(middleware.CORS(cfg))
+	engine.GET("/protected", func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	})
+	return engine
+}
+
+func serveMethod(engine *gin.Engine, method, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, http.NoBody)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS_Origins(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCORS(middleware.CORSConfig{
+		AllowedOrigins: []string{"https://pokedex.example.com", "https://*.example.org"},
+		AllowedMethods: []string{http.MethodGet},
+	})
+
+	testCases := map[string]struct {
+		origin  string
+		allowed bool
+	}{
+		"exact":                {origin: "https://pokedex.example.com", allowed: true},
+		"exact_case":           {origin: "https://Pokedex.Example.com", allowed: true},
+		"wildcard":             {origin: "https://app.example.org", allowed: true},
+		"wildcard_nested":      {origin: "https://beta.app.example.org", allowed: true},
+		"wildcard_apex":        {origin: "https://example.org", allowed: false},
+		"wildcard_scheme":      {origin: "http://app.example.org", allowed: false},
+		"wildcard_lookalike":   {origin: "https://app.evilexample.org", allowed: false},
+		"wildcard_port":        {origin: "https://app.example.org:8443", allowed: false},
+		"other_scheme":         {origin: "http://pokedex.example.com", allowed: false},
+		"other_origin":         {origin: "https://evil.example.net", allowed: false},
+		"exact_suffix_attempt": {origin: "https://pokedex.example.com.evil.net", allowed: false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serveMethod(engine, http.MethodGet, "/protected", map[string]string{"Origin": tc.origin})
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, "Origin", w.Header().Get("Vary"))
+			if !tc.allowed {
+				assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+				return
+			}
+			assert.Equal(t, tc.origin, w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "RateLimit-Remaining")
+		})
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCORS(middleware.CORSConfig{
+		AllowedOrigins:   []string{"https://pokedex.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Authorization", "X-API-Key"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	preflight := map[string]string{
+		"Origin":                         "https://pokedex.example.com",
+		"Access-Control-Request-Method":  http.MethodGet,
+		"Access-Control-Request-Headers": "authorization",
+	}
+
+	// The preflight request is answered before reaching the route rejecting the requests
+	w := serveMethod(engine, http.MethodOptions, "/protected", preflight)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://pokedex.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization, X-API-Key", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, []string{"Origin", "Access-Control-Request-Method, Access-Control-Request-Headers"}, w.Header().Values("Vary"))
+
+	// The preflight request from another origin is answered without CORS headers
+	preflight["Origin"] = "https://evil.example.net"
+	w = serveMethod(engine, http.MethodOptions, "/protected", preflight)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Methods"))
+
+	// The OPTIONS requests that are not preflights are not answered by the middleware
+	w = serveMethod(engine, http.MethodOptions, "/protected", map[string]string{"Origin": "https://pokedex.example.com"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCORS_AnyOrigin(t *testing.T) {
+	t.Parallel()
+
+	engine := setupCORS(middleware.CORSConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{http.MethodGet}})
+
+	w := serveMethod(engine, http.MethodGet, "/protected", map[string]string{"Origin": "https://anywhere.example.com"})
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Vary"))
+
+	// The credentials are rejected by the validation, and never allowed from any origin anyway
+	cfg := middleware.CORSConfig{AllowedOrigins: []string{"https://pokedex.example.com", "*"}, AllowCredentials: true}
+	require.ErrorIs(t, cfg.Validate(), errors.ErrInvalidConfiguration)
+	engine = setupCORS(cfg)
+
+	w = serveMethod(engine, http.MethodGet, "/protected", map[string]string{"Origin": "https://anywhere.example.com"})
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -3,15 +3,18 @@ package middleware
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/fra98/pokedex/pkg/logging"
 	"github.com/fra98/pokedex/pkg/server/httperror"
 )
 
-// ErrorHandler is a middleware that handles errors and returns a JSON response.
-// If the error is of type errors.HTTP, it will return the error message and status code.
-// If the error is of any other type, it will return a generic error message and status code 500.
+// ErrorHandler is a middleware that handles errors and returns an RFC 9457 problem details response.
+// If the error is of type httperror.HTTPError, it will be returned as is, with the Retry-After header if set.
+// If the error is of any other type, it will return a generic internal error and status code 500.
+// The problems carry the request path as instance and the request ID, if any.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
@@ -19,10 +22,25 @@ func ErrorHandler() gin.HandlerFunc {
 			var httperr httperror.HTTPError
 			switch {
 			case errors.As(err.Err, &httperr):
-				c.AbortWithStatusJSON(httperr.StatusCode, httperr)
+				if httperr.RetryAfter > 0 {
+					c.Header("Retry-After", strconv.Itoa(ceilSeconds(httperr.RetryAfter)))
+				}
+				abortWithProblem(c, httperr)
 			default:
-				c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]string{"message": "Internal Server Error"})
+				abortWithProblem(c, httperror.NewHTTPError(http.StatusInternalServerError, httperror.CodeInternalError, ""))
 			}
 		}
 	}
 }
+
+// abortWithProblem aborts the request replying with the given problem, completed with the request details.
+func abortWithProblem(c *gin.Context, httperr httperror.HTTPError) {
+	if httperr.Instance == "" {
+		httperr.Instance = c.Request.URL.Path
+	}
+	httperr.RequestID = logging.RequestID(c.Request.Context())
+
+	// The JSON renderer keeps the content type if already set
+	c.Header("Content-Type", httperror.ContentType)
+	c.AbortWithStatusJSON(httperr.Status, httperr)
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/cache"
+)
+
+const (
+	// degradedKey is the context key marking a response as degraded.
+	degradedKey = "httpcache.degraded"
+	// maxLastModifiedEntries is the maximum number of representations whose first-seen time is tracked.
+	maxLastModifiedEntries = 10000
+)
+
+// HTTPCacheConfig contains the configuration of the HTTPCache middleware.
+type HTTPCacheConfig struct {
+	// MaxAge is the max-age of the successful responses.
+	MaxAge time.Duration
+	// DegradedMaxAge is the max-age of the successful responses marked as degraded.
+	DegradedMaxAge time.Duration
+	// Private restricts the caching to the clients, e.g., for the authorized routes, so that the shared caches
+	// (proxies and CDNs) do not serve the responses to other clients, bypassing the authorization and the quotas.
+	Private bool
+}
+
+// MarkDegraded marks the response as degraded (e.g., the translation fell back to the original text),
+// so that it is cached by the clients for a shorter time.
+func MarkDegraded(c *gin.Context) {
+	c.Set(degradedKey, true)
+}
+
+// HTTPCache is a middleware that sets the HTTP caching headers of the successful GET responses.
+// It computes a strong ETag from the response body, sets the Cache-Control max-age according to the configuration,
+// capped at the remaining time to live of the cache entries serving the response, if any,
+// and sets Last-Modified to the first time the same representation was served.
+// Conditional requests (If-None-Match, If-Modified-Since) matching the response are answered with 304 Not Modified.
+func HTTPCache(cfg HTTPCacheConfig) gin.HandlerFunc {
+	lastModified := newLastModifiedTracker()
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		ctx, freshness := cache.WithFreshness(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		// Errors are rendered by the ErrorHandler middleware, and other responses are sent as they are
+		if len(c.Errors) > 0 || writer.Status() != http.StatusOK {
+			if writer.body.Len() > 0 {
+				_, _ = c.Writer.Write(writer.body.Bytes())
+			}
+			return
+		}
+
+		body := writer.body.Bytes()
+		sum := sha256.Sum256(body)
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		modified := lastModified.get(c.FullPath(), etag)
+
+		maxAge := cfg.MaxAge
+		if c.GetBool(degradedKey) {
+			maxAge = cfg.DegradedMaxAge
+		}
+		// The response is not cached longer than the cache entries it was built from, if served by any
+		if ttl, found := freshness.TTL(); found {
+			maxAge = min(maxAge, ttl)
+		}
+
+		header := c.Writer.Header()
+		header.Set("ETag", etag)
+		header.Set("Last-Modified", modified.Format(http.TimeFormat))
+		visibility := "public"
+		if cfg.Private {
+			visibility = "private"
+		}
+		header.Set("Cache-Control", visibility+", max-age="+strconv.Itoa(int(maxAge.Seconds())))
+
+		if notModified(c.Request, etag, modified) {
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+		_, _ = c.Writer.Write(body)
+	}
+}
+
+// notModified returns true if the conditional headers of the request match the given representation.
+// If-Modified-Since is evaluated only in absence of If-None-Match, as mandated by RFC 9110.
+func notModified(req *http.Request, etag string, modified time.Time) bool {
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+	if ifModifiedSince := req.Header.Get("If-Modified-Since"); ifModifiedSince != "" {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !modified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// etagMatches returns true if the If-None-Match header value matches the ETag, using the weak comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// lastModifiedTracker keeps track of the first time each representation of a route was served.
+type lastModifiedTracker struct {
+	mu    sync.Mutex
+	times map[string]time.Time
+}
+
+func newLastModifiedTracker() *lastModifiedTracker {
+	return &lastModifiedTracker{times: make(map[string]time.Time)}
+}
+
+// get returns the first time the representation was served, recording the current time if it is a new one.
+func (t *lastModifiedTracker) get(route, etag string) time.Time {
+	key := route + " " + etag
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if modified, found := t.times[key]; found {
+		return modified
+	}
+	// Bound the memory footprint, forgetting all the representations when the limit is reached
+	if len(t.times) >= maxLastModifiedEntries {
+		clear(t.times)
+	}
+	modified := time.Now().UTC()
+	t.times[key] = modified
+	return modified
+}
@@ -0,0 +1,122 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+)
+
+// setupHTTPCache is a helper function to setup a gin engine with routes served through the HTTPCache middleware.
+func setupHTTPCache() *gin.Engine {
+	engine := gin.New()
+	engine.Use(middleware.ErrorHandler())
+
+	cfg := middleware.HTTPCacheConfig{MaxAge: time.Hour, DegradedMaxAge: time.Minute}
+	engine.GET("/ok", middleware.HTTPCache(cfg), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"name": "mewtwo"})
+	})
+	engine.GET("/degraded", middleware.HTTPCache(cfg), func(c *gin.Context) {
+		middleware.MarkDegraded(c)
+		c.JSON(http.StatusOK, gin.H{"name": "mewtwo"})
+	})
+	engine.GET("/cached", middleware.HTTPCache(cfg), func(c *gin.Context) {
+		cache.ObserveExpiration(c.Request.Context(), time.Now().Add(10*time.Minute+500*time.Millisecond))
+		cache.ObserveExpiration(c.Request.Context(), time.Now().Add(20*time.Minute))
+		c.JSON(http.StatusOK, gin.H{"name": "mewtwo"})
+	})
+	engine.GET("/error", middleware.HTTPCache(cfg), func(c *gin.Context) {
+		_ = c.Error(httperror.NewHTTPError(http.StatusNotFound, httperror.CodePokemonNotFound, "not found"))
+	})
+	return engine
+}
+
+func serve(engine *gin.Engine, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestHTTPCache_ETag(t *testing.T) {
+	t.Parallel()
+
+	engine := setupHTTPCache()
+
+	w := serve(engine, "/ok", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"name": "mewtwo"}`, w.Body.String())
+	assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, w.Header().Get("Last-Modified"))
+
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.NotContains(t, etag, "W/", "ETag should be strong")
+
+	// Same representation, same ETag
+	assert.Equal(t, etag, serve(engine, "/ok", nil).Header().Get("ETag"))
+
+	// Matching If-None-Match is answered with 304 and no body
+	w = serve(engine, "/ok", map[string]string{"If-None-Match": `"other", ` + etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	// Not matching If-None-Match is answered with the full response
+	w = serve(engine, "/ok", map[string]string{"If-None-Match": `"other"`})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Body.String())
+}
+
+func TestHTTPCache_IfModifiedSince(t *testing.T) {
+	t.Parallel()
+
+	engine := setupHTTPCache()
+
+	lastModified := serve(engine, "/ok", nil).Header().Get("Last-Modified")
+
+	w := serve(engine, "/ok", map[string]string{"If-Modified-Since": lastModified})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	past := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	w = serve(engine, "/ok", map[string]string{"If-Modified-Since": past})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHTTPCache_Degraded(t *testing.T) {
+	t.Parallel()
+
+	w := serve(setupHTTPCache(), "/degraded", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+}
+
+func TestHTTPCache_CachedMaxAge(t *testing.T) {
+	t.Parallel()
+
+	// The max-age is the remaining time to live of the earliest expiring cache entry serving the response
+	w := serve(setupHTTPCache(), "/cached", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=600", w.Header().Get("Cache-Control"))
+}
+
+func TestHTTPCache_Error(t *testing.T) {
+	t.Parallel()
+
+	w := serve(setupHTTPCache(), "/error", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), "not found")
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/server/httperror"
+)
+
+// Logger is a middleware that logs the served requests, with their status code and latency.
+// Server errors are logged at error level, client errors at warn level, and the others at info level.
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("clientIp", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		}
+		if errs := c.Errors.ByType(gin.ErrorTypeAny); len(errs) > 0 {
+			attrs = append(attrs, slog.String("error", errs.String()))
+		}
+		slog.LogAttrs(c.Request.Context(), level, "Request served", attrs...)
+	}
+}
+
+// Recovery is a middleware that recovers from panics, logging them and replying 500 Internal Server Error.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		slog.ErrorContext(c.Request.Context(), "Panic recovered", "panic", recovered, "stack", string(debug.Stack()))
+		abortWithProblem(c, httperror.NewHTTPError(http.StatusInternalServerError, httperror.CodeInternalError, ""))
+	})
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/metrics"
+)
+
+// unmatchedRoute is the route label of the requests not matching any route, to bound the metrics cardinality.
+const unmatchedRoute = "unmatched"
+
+// Metrics is a middleware that records the count, latency and in-flight number of the requests,
+// by route template (e.g., /v1/pokemon/:name), method and status code.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		done := m.RequestStarted()
+		defer done()
+
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.ObserveRequest(route, c.Request.Method, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,43 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fra98/pokedex/pkg/metrics"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+	engine := gin.New()
+	engine.Use(middleware.Metrics(m), middleware.ErrorHandler())
+	engine.GET("/v1/pokemon/:name", func(c *gin.Context) {
+		if c.Param("name") == "missingno" {
+			_ = c.Error(httperror.NewHTTPError(http.StatusNotFound, httperror.CodePokemonNotFound, "not found"))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"name": c.Param("name")})
+	})
+
+	serve(engine, "/v1/pokemon/mewtwo", nil)
+	serve(engine, "/v1/pokemon/pikachu", nil)
+	serve(engine, "/v1/pokemon/missingno", nil)
+	serve(engine, "/unknown", nil)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	body := w.Body.String()
+
+	// Requests are labeled by route template, with the status code set by the error handler
+	assert.Contains(t, body, `pokedex_http_requests_total{method="GET",route="/v1/pokemon/:name",status="200"} 2`)
+	assert.Contains(t, body, `pokedex_http_requests_total{method="GET",route="/v1/pokemon/:name",status="404"} 1`)
+	assert.Contains(t, body, `pokedex_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/ratelimit"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+)
+
+// clientIDKey is the context key of the ID of the authenticated client.
+const clientIDKey = "ratelimit.clientID"
+
+// SetClientID sets the ID of the authenticated client (e.g., its API key ID),
+// so that it is rate limited by ID instead of by IP address.
+func SetClientID(c *gin.Context, id string) {
+	c.Set(clientIDKey, id)
+}
+
+// clientKey returns the key identifying the client: its ID if authenticated, otherwise its IP address.
+func clientKey(c *gin.Context) string {
+	if id := c.GetString(clientIDKey); id != "" {
+		return "id:" + id
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimit is a middleware that limits the requests of each client to the route group with a token bucket,
+// kept in the given store. The limit is advertised with the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers,
+// and the requests exceeding it are rejected with 429 Too Many Requests and the Retry-After header.
+// If the store fails, the requests are allowed.
+func RateLimit(store ratelimit.Store, group string, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res, err := store.Take(c.Request.Context(), group+":"+clientKey(c), limit)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "Rate limit store failure, allowing the request", "group", group, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+		if !res.Allowed {
+			httpErr := httperror.NewHTTPError(http.StatusTooManyRequests, httperror.CodeRateLimited,
+				"too many requests, retry in "+strconv.Itoa(ceilSeconds(res.RetryAfter))+" seconds")
+			httpErr.RetryAfter = res.RetryAfter
+			_ = c.Error(httpErr)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ceilSeconds returns the duration in seconds, rounded up.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/ratelimit"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+)
+
+// setupRateLimit is a helper function to setup a gin engine with a route limited to one request per client.
+// The requests carrying the X-Client-ID header are identified by it, the others by IP address.
+func setupRateLimit() *gin.Engine {
+	engine := gin.New()
+	engine.Use(middleware.ErrorHandler())
+	engine.GET("/limited", func(c *gin.Context) {
+		if id := c.GetHeader("X-Client-ID"); id != "" {
+			middleware.SetClientID(c, id)
+		}
+	}, middleware.RateLimit(ratelimit.NewMemoryStore(), "test", ratelimit.Limit{Rate: 0.5, Burst: 1}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return engine
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	engine := setupRateLimit()
+	client := map[string]string{"X-Forwarded-For": "203.0.113.1"}
+
+	w := serve(engine, "/limited", client)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Reset"))
+
+	// The limit of the client is exceeded
+	w = serve(engine, "/limited", client)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	var problem httperror.HTTPError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, httperror.CodeRateLimited, problem.Code)
+
+	// The other clients are not affected, whether identified by IP address or by ID
+	w = serve(engine, "/limited", map[string]string{"X-Forwarded-For": "203.0.113.2"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serve(engine, "/limited", map[string]string{"X-Forwarded-For": "203.0.113.1", "X-Client-ID": "sdk"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve(engine, "/limited", map[string]string{"X-Forwarded-For": "203.0.113.3", "X-Client-ID": "sdk"})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
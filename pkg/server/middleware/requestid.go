@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fra98/pokedex/pkg/logging"
+)
+
+// RequestIDHeader is the header carrying the request ID, both in the requests and in the responses.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the maximum length of the request IDs accepted from the clients.
+const maxRequestIDLength = 128
+
+// RequestID is a middleware that accepts the request ID from the X-Request-ID header, or generates a new one
+// if missing or invalid. The ID is echoed in the response header, and carried by the request context,
+// so that it is added to all the log records of the request.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// validRequestID returns whether the request ID is not empty, not too long, and made of printable ASCII characters only,
+// so that it cannot be used to inject content in the logs.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // never returns an error
+	return hex.EncodeToString(b)
+}
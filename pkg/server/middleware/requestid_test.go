@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/logging"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+)
+
+func setupRequestID() *gin.Engine {
+	engine := gin.New()
+	engine.Use(middleware.RequestID(), middleware.ErrorHandler())
+	engine.GET("/ok", func(c *gin.Context) {
+		c.String(http.StatusOK, logging.RequestID(c.Request.Context()))
+	})
+	engine.GET("/error", func(c *gin.Context) {
+		_ = c.Error(httperror.NewHTTPError(http.StatusNotFound, httperror.CodePokemonNotFound, "not found"))
+	})
+	return engine
+}
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	engine := setupRequestID()
+
+	// The request ID of the client is propagated through the context and echoed
+	w := serve(engine, "/ok", map[string]string{middleware.RequestIDHeader: "client-id-1"})
+	assert.Equal(t, "client-id-1", w.Header().Get(middleware.RequestIDHeader))
+	assert.Equal(t, "client-id-1", w.Body.String())
+
+	// Missing or invalid request IDs are replaced by generated ones
+	for _, requestID := range []string{"", "with spaces", strings.Repeat("a", 200)} {
+		w = serve(engine, "/ok", map[string]string{middleware.RequestIDHeader: requestID})
+		generated := w.Header().Get(middleware.RequestIDHeader)
+		assert.Len(t, generated, 32)
+		assert.Equal(t, generated, w.Body.String())
+	}
+}
+
+func TestRequestID_ErrorBody(t *testing.T) {
+	t.Parallel()
+
+	w := serve(setupRequestID(), "/error", map[string]string{middleware.RequestIDHeader: "client-id-2"})
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	var body httperror.HTTPError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "client-id-2", body.RequestID)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"bytes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedWriter is a gin.ResponseWriter buffering the response body instead of sending it,
+// so that a middleware can inspect and alter the response once the handlers completed.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
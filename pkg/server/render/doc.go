@@ -0,0 +1,2 @@
+// Package render provides the content negotiation of the API responses, rendered as JSON, YAML, XML, MessagePack or CSV.
+package render
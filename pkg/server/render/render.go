@@ -0,0 +1,197 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/server/httperror"
+)
+
+// formatKey is the context key of the negotiated format.
+const formatKey = "render.format"
+
+// FormatQuery is the query parameter selecting the format, taking precedence over the Accept header.
+const FormatQuery = "format"
+
+// Format is a format of the API responses.
+type Format string
+
+// Formats of the API responses.
+const (
+	FormatJSON    Format = "json"
+	FormatYAML    Format = "yaml"
+	FormatXML     Format = "xml"
+	FormatMsgPack Format = "msgpack"
+	FormatCSV     Format = "csv"
+)
+
+// Formats of the resources and of the lists of resources, in order of preference when accepted with the same quality.
+var (
+	ResourceFormats = []Format{FormatJSON, FormatYAML, FormatXML, FormatMsgPack}
+	ListFormats     = []Format{FormatJSON, FormatYAML, FormatXML, FormatMsgPack, FormatCSV}
+)
+
+// mediaTypes are the media types of each format. The first one is the content type of the responses.
+var mediaTypes = map[Format][]string{
+	FormatJSON:    {"application/json"},
+	FormatYAML:    {"application/yaml", "application/x-yaml", "text/yaml"},
+	FormatXML:     {"application/xml", "text/xml"},
+	FormatMsgPack: {"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"},
+	FormatCSV:     {"text/csv"},
+}
+
+// Table is implemented by the lists of resources renderable as CSV, with a record per resource.
+type Table interface {
+	// Header returns the names of the columns.
+	Header() []string
+	// Records returns the values of the columns of each resource.
+	Records() [][]string
+}
+
+// Negotiate is a middleware that negotiates the format of the response among the given ones, from the format query parameter
+// or, in its absence, from the Accept header. The first format is the default one, if the request expresses no preference.
+// The requests accepting none of the formats are rejected with 406 Not Acceptable.
+func Negotiate(formats ...Format) gin.HandlerFunc {
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		names = append(names, string(format))
+	}
+	detail := "the supported formats are " + strings.Join(names, ", ")
+
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept")
+
+		format, ok := negotiate(formats, c.Query(FormatQuery), c.GetHeader("Accept"))
+		if !ok {
+			_ = c.Error(httperror.NewHTTPError(http.StatusNotAcceptable, httperror.CodeNotAcceptable, detail))
+			c.Abort()
+			return
+		}
+		c.Set(formatKey, format)
+		c.Next()
+	}
+}
+
+// Render renders the object in the negotiated format, JSON if not negotiated.
+// The object must implement Table to be rendered as CSV.
+func Render(c *gin.Context, status int, obj any) {
+	format, _ := c.Value(formatKey).(Format)
+	switch format {
+	case FormatYAML:
+		c.Render(status, render.YAML{Data: obj})
+	case FormatXML:
+		c.Render(status, render.XML{Data: obj})
+	case FormatMsgPack:
+		c.Render(status, render.MsgPack{Data: obj})
+	case FormatCSV:
+		table, ok := obj.(Table)
+		if !ok {
+			_ = c.Error(fmt.Errorf("%T as CSV: %w", obj, errors.ErrUnsupportedFormat))
+			return
+		}
+		data, err := writeCSV(table)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		c.Data(status, mediaTypes[FormatCSV][0]+"; charset=utf-8", data)
+	default:
+		c.JSON(status, obj)
+	}
+}
+
+// writeCSV returns the table in the CSV format, with the header as first record.
+func writeCSV(table Table) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(table.Header()); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	if err := w.WriteAll(table.Records()); err != nil {
+		return nil, fmt.Errorf("failed to write CSV records: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// negotiate returns the format requested by the format query parameter, if any, otherwise the format
+// with the highest quality in the Accept header. Each format gets the quality of the most specific media range matching it.
+func negotiate(formats []Format, query, accept string) (Format, bool) {
+	if query != "" {
+		format := Format(strings.ToLower(query))
+		return format, slices.Contains(formats, format)
+	}
+	if strings.TrimSpace(accept) == "" {
+		return formats[0], true
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQuality := Format(""), 0.0
+	for _, format := range formats {
+		if q := quality(format, ranges); q > bestQuality {
+			best, bestQuality = format, q
+		}
+	}
+	return best, best != ""
+}
+
+// mediaRange is a media range of the Accept header, along with its quality.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for item := range strings.SplitSeq(accept, ",") {
+		mediaType, params, _ := strings.Cut(item, ";")
+		typ, subtype, found := strings.Cut(strings.ToLower(strings.TrimSpace(mediaType)), "/")
+		if !found {
+			continue
+		}
+
+		q := 1.0
+		for param := range strings.SplitSeq(params, ";") {
+			if value, found := strings.CutPrefix(strings.TrimSpace(param), "q="); found {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, q: q})
+	}
+	return ranges
+}
+
+// quality returns the quality of the format, given by the most specific media range matching one of its media types.
+func quality(format Format, ranges []mediaRange) float64 {
+	q, specificity := 0.0, -1
+	for _, mediaType := range mediaTypes[format] {
+		typ, subtype, _ := strings.Cut(mediaType, "/")
+		for _, r := range ranges {
+			var s int
+			switch {
+			case r.typ == typ && r.subtype == subtype:
+				s = 2
+			case r.typ == typ && r.subtype == "*":
+				s = 1
+			case r.typ == "*" && r.subtype == "*":
+				s = 0
+			default:
+				continue
+			}
+			if s > specificity || (s == specificity && r.q > q) {
+				q, specificity = r.q, s
+			}
+		}
+	}
+	return q
+}
@@ -0,0 +1,133 @@
+package render_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+
+	"github.com/fra98/pokedex/pkg/server/httperror"
+	"github.com/fra98/pokedex/pkg/server/middleware"
+	"github.com/fra98/pokedex/pkg/server/render"
+)
+
+type pokemon struct {
+	XMLName xml.Name `json:"-"    xml:"pokemon" yaml:"-"`
+	Name    string   `json:"name" xml:"name"    yaml:"name"`
+}
+
+type pokemonList []pokemon
+
+func (l pokemonList) Header() []string {
+	return []string{"name"}
+}
+
+func (l pokemonList) Records() [][]string {
+	records := make([][]string, 0, len(l))
+	for _, p := range l {
+		records = append(records, []string{p.Name})
+	}
+	return records
+}
+
+// setupRender is a helper function to setup a gin engine serving a resource and a list of resources.
+func setupRender() *gin.Engine {
+	engine := gin.New()
+	engine.Use(middleware.ErrorHandler())
+	engine.GET("/pokemon", render.Negotiate(render.ResourceFormats...), func(c *gin.Context) {
+		render.Render(c, http.StatusOK, pokemon{Name: "mewtwo"})
+	})
+	engine.GET("/list", render.Negotiate(render.ListFormats...), func(c *gin.Context) {
+		render.Render(c, http.StatusOK, pokemonList{{Name: "mewtwo"}, {Name: "mew, the \"ancestor\""}})
+	})
+	return engine
+}
+
+func get(engine *gin.Engine, target, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	engine := setupRender()
+
+	testCases := map[string]struct {
+		target      string
+		accept      string
+		contentType string
+	}{
+		"default":            {target: "/pokemon", contentType: "application/json; charset=utf-8"},
+		"any":                {target: "/pokemon", accept: "*/*", contentType: "application/json; charset=utf-8"},
+		"yaml":               {target: "/pokemon", accept: "application/yaml", contentType: "application/yaml; charset=utf-8"},
+		"yaml_alias":         {target: "/pokemon", accept: "text/yaml", contentType: "application/yaml; charset=utf-8"},
+		"xml":                {target: "/pokemon", accept: "text/xml", contentType: "application/xml; charset=utf-8"},
+		"msgpack":            {target: "/pokemon", accept: "application/x-msgpack", contentType: "application/msgpack; charset=utf-8"},
+		"quality":            {target: "/pokemon", accept: "application/json;q=0.5, application/xml", contentType: "application/xml; charset=utf-8"},
+		"specific_over_any":  {target: "/pokemon", accept: "application/json;q=0, */*", contentType: "application/yaml; charset=utf-8"},
+		"subtype_wildcard":   {target: "/pokemon", accept: "text/*", contentType: "application/yaml; charset=utf-8"},
+		"browser":            {target: "/pokemon", accept: "text/html,application/xhtml+xml,*/*;q=0.8", contentType: "application/json; charset=utf-8"},
+		"query":              {target: "/pokemon?format=yaml", accept: "application/json", contentType: "application/yaml; charset=utf-8"},
+		"query_case":         {target: "/pokemon?format=XML", contentType: "application/xml; charset=utf-8"},
+		"csv":                {target: "/list", accept: "text/csv", contentType: "text/csv; charset=utf-8"},
+		"csv_query":          {target: "/list?format=csv", contentType: "text/csv; charset=utf-8"},
+		"list_default":       {target: "/list", accept: "text/csv;q=0.5, application/json", contentType: "application/json; charset=utf-8"},
+		"unacceptable":       {target: "/pokemon", accept: "text/html", contentType: httperror.ContentType},
+		"unacceptable_csv":   {target: "/pokemon", accept: "text/csv", contentType: httperror.ContentType},
+		"unacceptable_query": {target: "/pokemon?format=csv", contentType: httperror.ContentType},
+		"rejected":           {target: "/pokemon", accept: "application/json;q=0", contentType: httperror.ContentType},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			w := get(engine, tc.target, tc.accept)
+			assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, "Accept", w.Header().Get("Vary"))
+			if tc.contentType == httperror.ContentType {
+				assert.Equal(t, http.StatusNotAcceptable, w.Code)
+				var problem httperror.HTTPError
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				assert.Equal(t, httperror.CodeNotAcceptable, problem.Code)
+				return
+			}
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	engine := setupRender()
+
+	w := get(engine, "/pokemon", "application/yaml")
+	var fromYAML pokemon
+	require.NoError(t, yaml.Unmarshal(w.Body.Bytes(), &fromYAML))
+	assert.Equal(t, "mewtwo", fromYAML.Name)
+
+	w = get(engine, "/pokemon", "application/xml")
+	assert.Equal(t, "<pokemon><name>mewtwo</name></pokemon>", w.Body.String())
+
+	w = get(engine, "/pokemon", "application/msgpack")
+	var fromMsgPack map[string]any
+	require.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), &codec.MsgpackHandle{}).Decode(&fromMsgPack))
+	assert.Equal(t, map[string]any{"name": []byte("mewtwo")}, fromMsgPack)
+
+	w = get(engine, "/list", "text/csv")
+	assert.Equal(t, "name\nmewtwo\n\"mew, the \"\"ancestor\"\"\"\n", w.Body.String())
+}
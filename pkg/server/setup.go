@@ -1,31 +1,163 @@
 package server
 
 import (
+	"slices"
+
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"github.com/fra98/pokedex/pkg/api"
+	"github.com/fra98/pokedex/pkg/auth"
+	"github.com/fra98/pokedex/pkg/metrics"
+	"github.com/fra98/pokedex/pkg/ratelimit"
 	"github.com/fra98/pokedex/pkg/server/middleware"
+	"github.com/fra98/pokedex/pkg/server/render"
+	"github.com/fra98/pokedex/pkg/tracing"
 )
 
-// SetupEngine sets up and returns a new Gin engine.
+// SetupEngine sets up and returns a new Gin engine, with the request ID, structured logger and recovery middlewares attached.
 func SetupEngine() *gin.Engine {
-	return gin.Default() // default Gin engine with Logger and Recovery middleware already attached
+	engine := gin.New()
+	engine.Use(middleware.RequestID(), middleware.Logger(), middleware.Recovery())
+	return engine
 }
 
 // SetupMiddlewares sets up the middlewares for the server engine.
-func SetupMiddlewares(r *gin.Engine) {
+// If metrics are provided, the requests are recorded, including the ones failed with errors.
+// If CORS is enabled, the cross-origin requests from the allowed origins are accepted.
+// If the compression configuration is provided, the response bodies are compressed.
+func SetupMiddlewares(r *gin.Engine, m *metrics.Metrics, cors middleware.CORSConfig, compress *middleware.CompressConfig) {
+	// Register the tracing middleware, continuing the trace of the W3C traceparent header, if any
+	r.Use(otelgin.Middleware(tracing.ServiceName))
+
+	// Register the metrics middleware, before the error handler to observe the final status codes
+	if m != nil {
+		r.Use(middleware.Metrics(m))
+	}
+
+	// Register the CORS middleware, answering the preflight requests before the route middlewares (e.g., authorization)
+	if cors.Enabled() {
+		r.Use(middleware.CORS(cors))
+	}
+
+	// Register the compression middleware, before the error handler to compress the problem responses too
+	if compress != nil {
+		r.Use(middleware.Compress(*compress))
+	}
+
 	// Register the error handler middleware
 	r.Use(middleware.ErrorHandler())
 }
 
+// Route groups sharing a rate limit.
+const (
+	RateLimitGroupPokemon    = "pokemon"
+	RateLimitGroupTranslated = "translated"
+)
+
+// RateLimitConfig contains the per-client rate limits of the route groups.
+// A limit is not enforced if the store is nil or the limit is not enabled.
+type RateLimitConfig struct {
+	Store      ratelimit.Store
+	Pokemon    ratelimit.Limit
+	Translated ratelimit.Limit
+}
+
+// handlers returns the rate limiting middleware of the route group, if enforced.
+func (cfg RateLimitConfig) handlers(group string, limit ratelimit.Limit) []gin.HandlerFunc {
+	if cfg.Store == nil || !limit.Enabled() {
+		return nil
+	}
+	return []gin.HandlerFunc{middleware.RateLimit(cfg.Store, group, limit)}
+}
+
+// AuthConfig contains the authenticator of the API keys and tokens authorizing the routes, according to the scope each route requires.
+// The routes are not authorized if the authenticator is nil. If anonymous requests are allowed,
+// the requests without credentials are let through, and they are rate limited by IP address.
+type AuthConfig struct {
+	Authenticator  *auth.Authenticator
+	AllowAnonymous bool
+}
+
+// handlers returns the authorization middleware of the routes requiring the scope, if enforced.
+func (cfg AuthConfig) handlers(scope auth.Scope) []gin.HandlerFunc {
+	if cfg.Authenticator == nil {
+		return nil
+	}
+	return []gin.HandlerFunc{middleware.Auth(cfg.Authenticator, scope, cfg.AllowAnonymous)}
+}
+
 // RegisterEndpoints registers the endpoints of the API to the server engine.
-func RegisterEndpoints(r *gin.Engine, pokeHandler *api.PokemonHandler) {
+// Each group of Pokemon endpoints requires its own scope, is rate limited per client,
+// and sets the HTTP caching headers according to the given configurations, restricting the caching to the clients if authorized.
+// The translated endpoints have a dedicated scope and rate limit, since they consume the shared FunTranslations quota.
+// The Pokemon listing and the search share the scope and rate limit of the Pokemon endpoints,
+// and they are registered only if their handlers are provided.
+func RegisterEndpoints(r *gin.Engine, pokeHandler *api.PokemonHandler, catalogHandler *api.CatalogHandler, searchHandler *api.SearchHandler,
+	httpCache middleware.HTTPCacheConfig, rateLimit RateLimitConfig, authCfg AuthConfig) {
 	v1 := r.Group("/v1")
+	if authCfg.Authenticator != nil {
+		httpCache.Private = true
+	}
 
-	// Health check endpoint
+	// Health check endpoint, kept as an alias of the liveness endpoint
 	v1.GET("/health", api.IsHealthy)
 
-	// Pokemon endpoints
-	v1.GET("/pokemon/:name", pokeHandler.GetPokemon)
-	v1.GET("/pokemon/translated/:name", pokeHandler.GetTranslatedPokemon)
+	// Pokemon endpoints, authorized before being rate limited to identify the clients by API key
+	pokemon := v1.Group("/pokemon", slices.Concat(
+		authCfg.handlers(auth.ScopeRead),
+		rateLimit.handlers(RateLimitGroupPokemon, rateLimit.Pokemon),
+	)...)
+	pokemon.GET("/:name", render.Negotiate(render.ResourceFormats...), middleware.HTTPCache(httpCache), pokeHandler.GetPokemon)
+	if catalogHandler != nil {
+		pokemon.GET("", render.Negotiate(render.ListFormats...), middleware.HTTPCache(httpCache), catalogHandler.ListPokemon)
+	}
+
+	// Search endpoint, not cached by the clients since the results improve as the search index grows
+	if searchHandler != nil {
+		search := v1.Group("/search", slices.Concat(
+			authCfg.handlers(auth.ScopeRead),
+			rateLimit.handlers(RateLimitGroupPokemon, rateLimit.Pokemon),
+		)...)
+		search.GET("", render.Negotiate(render.ListFormats...), searchHandler.Search)
+	}
+
+	translated := v1.Group("/pokemon/translated", slices.Concat(
+		authCfg.handlers(auth.ScopeTranslate),
+		rateLimit.handlers(RateLimitGroupTranslated, rateLimit.Translated),
+	)...)
+	translated.GET("/:name", render.Negotiate(render.ResourceFormats...), middleware.HTTPCache(httpCache), pokeHandler.GetTranslatedPokemon)
+}
+
+// RegisterHealthEndpoints registers the liveness and readiness endpoints to the server engine.
+func RegisterHealthEndpoints(r *gin.Engine, healthHandler *api.HealthHandler) {
+	r.GET("/livez", api.IsHealthy)
+	r.GET("/readyz", healthHandler.IsReady)
+}
+
+// RegisterMetricsEndpoint registers the endpoint exposing the Prometheus metrics to the server engine.
+func RegisterMetricsEndpoint(r *gin.Engine, m *metrics.Metrics) {
+	r.GET("/metrics", gin.WrapH(m.Handler()))
+}
+
+// RegisterAdminEndpoints registers the administration endpoints to the server engine.
+// The endpoints are authorized only for the requests carrying an API key or a token with the admin scope.
+// The API keys administration endpoints are registered only if the keys handler is provided.
+func RegisterAdminEndpoints(r *gin.Engine, authenticator *auth.Authenticator, cacheHandler *api.CacheAdminHandler,
+	keysHandler *api.APIKeyAdminHandler) {
+	admin := r.Group("/admin", middleware.Auth(authenticator, auth.ScopeAdmin, false))
+
+	// API keys administration endpoints
+	if keysHandler != nil {
+		admin.GET("/keys", render.Negotiate(render.ListFormats...), keysHandler.GetUsage)
+		admin.POST("/keys/:name/disable", keysHandler.DisableKey)
+		admin.POST("/keys/:name/enable", keysHandler.EnableKey)
+	}
+
+	// Cache administration endpoints
+	admin.GET("/cache/stats", cacheHandler.GetStats)
+	admin.GET("/cache/keys", render.Negotiate(render.ListFormats...), cacheHandler.ListKeys)
+	admin.DELETE("/cache/keys", cacheHandler.PurgeKeys)
+	admin.GET("/cache/entry", cacheHandler.GetEntry)
+	admin.DELETE("/cache/entry", cacheHandler.DeleteEntry)
 }
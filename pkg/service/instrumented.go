@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/fra98/pokedex/pkg/metrics"
+	"github.com/fra98/pokedex/pkg/models"
+)
+
+var _ Pokemon = &InstrumentedPokemonService{} // check if it implements the Pokemon interface.
+
+// InstrumentedPokemonService wraps a Pokemon service, recording the translation fallbacks by reason.
+type InstrumentedPokemonService struct {
+	service Pokemon
+	metrics *metrics.Metrics
+}
+
+// NewInstrumentedPokemonService creates a new InstrumentedPokemonService recording to the given metrics.
+func NewInstrumentedPokemonService(service Pokemon, m *metrics.Metrics) *InstrumentedPokemonService {
+	return &InstrumentedPokemonService{
+		service: service,
+		metrics: m,
+	}
+}
+
+// GetPokemonInfo retrieves the information of a Pokemon given its name.
+func (s *InstrumentedPokemonService) GetPokemonInfo(ctx context.Context, name string) (*models.PokemonResponse, error) {
+	return s.service.GetPokemonInfo(ctx, name) //nolint:wrapcheck // the decorator returns the errors as they are
+}
+
+// GetTranslatedPokemonInfo retrieves the information of a Pokemon given its name with a translated description.
+func (s *InstrumentedPokemonService) GetTranslatedPokemonInfo(ctx context.Context, name string) (*models.PokemonResponse, error) {
+	pokemon, err := s.service.GetTranslatedPokemonInfo(ctx, name)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // the decorator returns the errors as they are
+	}
+
+	if pokemon.TranslationFallback != "" {
+		s.metrics.ObserveTranslationFallback(pokemon.TranslationFallback)
+	}
+	return pokemon, nil
+}
@@ -2,14 +2,20 @@ package service
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"log/slog"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/fra98/pokedex/pkg/client/pokeapi"
 	"github.com/fra98/pokedex/pkg/client/translator"
 	"github.com/fra98/pokedex/pkg/consts"
 	"github.com/fra98/pokedex/pkg/errors"
 	"github.com/fra98/pokedex/pkg/models"
+	"github.com/fra98/pokedex/pkg/tracing"
 )
 
 var _ Pokemon = &PokemonService{}
@@ -29,7 +35,10 @@ func NewPokemonService(pokeClient pokeapi.Client, translatorClient translator.Cl
 }
 
 // GetPokemonInfo retrieves the information of a Pokemon given its name.
-func (s *PokemonService) GetPokemonInfo(ctx context.Context, name string) (*models.PokemonResponse, error) {
+func (s *PokemonService) GetPokemonInfo(ctx context.Context, name string) (_ *models.PokemonResponse, err error) {
+	ctx, span := tracing.Start(ctx, "PokemonService.GetPokemonInfo", trace.WithAttributes(attribute.String("pokemon.name", name)))
+	defer func() { tracing.End(span, err) }()
+
 	pokemonSpecies, err := s.pokeClient.GetPokemonSpecies(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve pokemon species: %w", err)
@@ -49,7 +58,10 @@ func (s *PokemonService) GetPokemonInfo(ctx context.Context, name string) (*mode
 }
 
 // GetTranslatedPokemonInfo retrieves the information of a Pokemon given its name with a translated description.
-func (s *PokemonService) GetTranslatedPokemonInfo(ctx context.Context, name string) (*models.PokemonResponse, error) {
+func (s *PokemonService) GetTranslatedPokemonInfo(ctx context.Context, name string) (_ *models.PokemonResponse, err error) {
+	ctx, span := tracing.Start(ctx, "PokemonService.GetTranslatedPokemonInfo", trace.WithAttributes(attribute.String("pokemon.name", name)))
+	defer func() { tracing.End(span, err) }()
+
 	// Get basic info first
 	pokemon, err := s.GetPokemonInfo(ctx, name)
 	if err != nil {
@@ -63,12 +75,19 @@ func (s *PokemonService) GetTranslatedPokemonInfo(ctx context.Context, name stri
 	} else {
 		translationType = consts.ShakespeareTranslationType
 	}
+	span.SetAttributes(attribute.String("translation.type", translationType))
 
 	// Get translation
 	translatedDesc, err := s.translatorClient.Translate(ctx, pokemon.Description, translationType)
 	if err != nil {
 		// if translation fails, fallback to original description
 		translatedDesc = pokemon.Description
+		pokemon.TranslationFallback = fallbackReason(err)
+		// The fallback is not a failure of the request, hence it is recorded as event instead of error
+		span.AddEvent("translation fallback", trace.WithAttributes(attribute.String("error.message", err.Error())))
+		span.SetAttributes(attribute.String("translation.fallback_reason", pokemon.TranslationFallback))
+		slog.WarnContext(ctx, "Translation failed, falling back to the original description",
+			"pokemon", name, "translationType", translationType, "reason", pokemon.TranslationFallback, "error", err)
 	}
 
 	// Update description
@@ -76,6 +95,20 @@ func (s *PokemonService) GetTranslatedPokemonInfo(ctx context.Context, name stri
 	return pokemon, nil
 }
 
+// Helper function to get the reason of a translation fallback from the translation error.
+func fallbackReason(err error) string {
+	switch {
+	case stderrors.Is(err, errors.ErrRateLimitExceeded):
+		return consts.FallbackReasonRateLimited
+	case stderrors.Is(err, errors.ErrCircuitOpen):
+		return consts.FallbackReasonCircuitOpen
+	case stderrors.Is(err, errors.ErrBulkheadFull):
+		return consts.FallbackReasonBusy
+	default:
+		return consts.FallbackReasonFailed
+	}
+}
+
 // Helper function to extract English description.
 func extractEnglishDescription(species *pokeapi.PokemonSpecies) (string, error) {
 	for i := range species.FlavorTextEntries {
@@ -84,7 +117,7 @@ func extractEnglishDescription(species *pokeapi.PokemonSpecies) (string, error)
 			return sanitizeDescription(entryFlavor.FlavorText), nil
 		}
 	}
-	return "", errors.ErrResourceNotFound
+	return "", errors.ErrDescriptionNotFound
 }
 
 // Helper function to sanitize description.
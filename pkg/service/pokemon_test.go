@@ -11,6 +11,8 @@ import (
 
 	"github.com/fra98/pokedex/pkg/client/pokeapi"
 	"github.com/fra98/pokedex/pkg/client/translator"
+	"github.com/fra98/pokedex/pkg/consts"
+	"github.com/fra98/pokedex/pkg/errors"
 	"github.com/fra98/pokedex/pkg/service"
 )
 
@@ -77,16 +79,16 @@ func setupService(pokeHandler, translatorHandler *http.HandlerFunc) (pokeServer,
 	// Create clients with test servers
 	var pokeClient pokeapi.Client
 	if pokeServer != nil {
-		pokeClient = pokeapi.NewPokeAPIClient(&pokeServer.URL)
+		pokeClient = pokeapi.NewPokeAPIClient(&pokeServer.URL, nil)
 	} else {
-		pokeClient = pokeapi.NewPokeAPIClient(nil)
+		pokeClient = pokeapi.NewPokeAPIClient(nil, nil)
 	}
 
 	var translatorClient translator.Client
 	if translServer != nil {
-		translatorClient = translator.NewFunTranslationClient(&translServer.URL)
+		translatorClient = translator.NewFunTranslationClient(&translServer.URL, nil)
 	} else {
-		translatorClient = translator.NewFunTranslationClient(nil)
+		translatorClient = translator.NewFunTranslationClient(nil, nil)
 	}
 
 	// Create the service
@@ -260,8 +262,9 @@ func TestGetTranslatedPokemonInfo_NoText(t *testing.T) {
 	// Call the service method
 	result, err := pokemonService.GetPokemonInfo(t.Context(), "mewtwo")
 
-	// Assertions - should get an error since there's no English text
-	require.Error(t, err)
+	// Assertions - should get an error since there's no English text, distinct from an unknown species
+	require.ErrorIs(t, err, errors.ErrDescriptionNotFound)
+	require.NotErrorIs(t, err, errors.ErrResourceNotFound)
 	assert.Nil(t, result)
 }
 
@@ -299,6 +302,7 @@ func TestGetTranslatedPokemonInfo_RateLimiting(t *testing.T) {
 	assert.Equal(t, "original description", result.Description)
 	assert.Equal(t, "rare", result.Habitat)
 	assert.True(t, result.IsLegendary)
+	assert.Equal(t, consts.FallbackReasonRateLimited, result.TranslationFallback)
 }
 
 func TestGetTranslatedPokemonInfo_FailurePoke(t *testing.T) {
@@ -316,7 +320,13 @@ func TestGetTranslatedPokemonInfo_FailurePoke(t *testing.T) {
 	// Call the service method
 	result, err := pokemonService.GetTranslatedPokemonInfo(t.Context(), "mewtwo")
 
-	// Assertions - should get an error
-	require.Error(t, err)
+	// Assertions - should get an upstream error
+	require.ErrorIs(t, err, errors.ErrResourceNotFound)
 	assert.Nil(t, result)
+
+	var upstreamErr *errors.UpstreamError
+	require.ErrorAs(t, err, &upstreamErr)
+	assert.Equal(t, "pokeapi", upstreamErr.Upstream)
+	assert.Equal(t, http.StatusNotFound, upstreamErr.StatusCode)
+	assert.NotErrorIs(t, err, errors.ErrUpstreamTimeout)
 }
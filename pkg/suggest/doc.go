@@ -0,0 +1,2 @@
+// Package suggest provides the "did you mean" suggestions of the Pokemon species names closest to a misspelled one.
+package suggest
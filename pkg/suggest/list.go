@@ -0,0 +1,128 @@
+package suggest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+var _ Source = &SpeciesList{} // check if it implements the Source interface.
+
+// ListConfig contains the configuration of the species list.
+type ListConfig struct {
+	// PageSize is the number of species retrieved per page of the species list.
+	PageSize int
+	// Timeout is the maximum duration of a retrieval of the species list.
+	Timeout time.Duration
+	// RetryInterval is the minimum interval between two attempts to retrieve the species list, after a failure.
+	RetryInterval time.Duration
+}
+
+// SpeciesList is a Source retrieving the species names from the PokeAPI on the first request, and keeping them afterwards.
+// The retrieval runs in background with its own context, shared by the concurrent requests, so that it is not canceled
+// if the request triggering it is, and the requests waiting for it do not block the others.
+type SpeciesList struct {
+	client pokeapi.Client
+	config ListConfig
+
+	mu sync.Mutex
+	// names are the retrieved species names, nil until retrieved.
+	names []string
+	// loading is closed once the retrieval in progress completes, nil if none is in progress.
+	loading chan struct{}
+	// err is the error of the last failed retrieval, at failedAt.
+	err      error
+	failedAt time.Time
+}
+
+// NewSpeciesList returns a new SpeciesList retrieving the species names from the given client.
+func NewSpeciesList(client pokeapi.Client, cfg *ListConfig) *SpeciesList {
+	config := *cfg
+	if config.PageSize <= 0 {
+		config.PageSize = 2000
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = time.Minute
+	}
+	return &SpeciesList{client: client, config: config}
+}
+
+// SpeciesNames returns the names of all the species, waiting for their retrieval, started if not in progress yet.
+// After a failure, the retrieval is attempted again only once the retry interval elapsed.
+func (l *SpeciesList) SpeciesNames(ctx context.Context) ([]string, error) {
+	l.mu.Lock()
+	if l.names != nil {
+		defer l.mu.Unlock()
+		return l.names, nil
+	}
+	loading := l.loading
+	if loading == nil {
+		if !l.failedAt.IsZero() && time.Since(l.failedAt) < l.config.RetryInterval {
+			defer l.mu.Unlock()
+			return nil, fmt.Errorf("%w, retrying after %s: %w", l.err, l.failedAt.Add(l.config.RetryInterval).Format(time.RFC3339),
+				errors.ErrNotReady)
+		}
+		loading = make(chan struct{})
+		l.loading = loading
+		go l.load(loading)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-loading:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("species list retrieval in progress: %w", ctx.Err())
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.names == nil {
+		return nil, l.err
+	}
+	return l.names, nil
+}
+
+// load retrieves the species names, closing the channel once completed.
+func (l *SpeciesList) load(done chan<- struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.config.Timeout)
+	defer cancel()
+	names, err := l.fetch(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to retrieve the species list for the suggestions", "error", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.loading = nil
+	if err != nil {
+		l.err, l.failedAt = fmt.Errorf("species list retrieval failed: %w", err), time.Now()
+		return
+	}
+	l.names = names
+}
+
+// fetch pages through the species list, returning the names of all the species.
+func (l *SpeciesList) fetch(ctx context.Context) ([]string, error) {
+	names := []string{}
+	for offset := 0; ; {
+		page, err := l.client.ListPokemonSpecies(ctx, offset, l.config.PageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list species at offset %d: %w", offset, err)
+		}
+		for _, species := range page.Results {
+			names = append(names, species.Name)
+		}
+
+		offset += len(page.Results)
+		if page.Next == nil || len(page.Results) == 0 {
+			return names, nil
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package suggest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/suggest"
+)
+
+// speciesList is the species list served by the fake PokeAPI, split in two pages.
+var speciesList = map[string]string{
+	"0": `{"count": 6, "next": "http://pokeapi/pokemon-species/?offset=3&limit=3", "results": [
+		{"name": "pikachu"}, {"name": "raichu"}, {"name": "pichu"}]}`,
+	"3": `{"count": 6, "next": null, "results": [{"name": "mew"}, {"name": "mewtwo"}, {"name": "mr-mime"}]}`,
+}
+
+var allSpecies = []string{"pikachu", "raichu", "pichu", "mew", "mewtwo", "mr-mime"}
+
+// setupPokeAPI is a helper function to setup a fake PokeAPI serving the species list, failing while failing is set.
+// If the gate is provided, the requests are answered only once it is closed. It returns the number of requests received.
+func setupPokeAPI(t *testing.T, failing *atomic.Bool, gate <-chan struct{}) (pokeapi.Client, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	pokeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if gate != nil {
+			<-gate
+		}
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, err := io.WriteString(w, speciesList[r.URL.Query().Get("offset")])
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(pokeServer.Close)
+
+	return pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), &requests
+}
+
+func TestSpeciesList(t *testing.T) {
+	t.Parallel()
+
+	gate := make(chan struct{})
+	client, requests := setupPokeAPI(t, &atomic.Bool{}, gate)
+	list := suggest.NewSpeciesList(client, &suggest.ListConfig{PageSize: 3})
+
+	// The caller canceled while waiting gets no names, without canceling the retrieval
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err := list.SpeciesNames(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// The concurrent callers share the same retrieval
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			names, err := list.SpeciesNames(t.Context())
+			assert.NoError(t, err)
+			assert.Equal(t, allSpecies, names)
+		}()
+	}
+	close(gate)
+	wg.Wait()
+
+	// The species names are retrieved only once, page by page
+	names, err := list.SpeciesNames(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, allSpecies, names)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestSpeciesList_Retry(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+	failing.Store(true)
+	client, requests := setupPokeAPI(t, &failing, nil)
+	list := suggest.NewSpeciesList(client, &suggest.ListConfig{RetryInterval: 100 * time.Millisecond})
+
+	// The failed retrieval is reported to the waiting callers
+	_, err := list.SpeciesNames(t.Context())
+	require.Error(t, err)
+	received := requests.Load()
+	assert.Positive(t, received)
+
+	// The retrieval is not attempted again before the retry interval elapsed
+	failing.Store(false)
+	_, err = list.SpeciesNames(t.Context())
+	require.ErrorIs(t, err, errors.ErrNotReady)
+	assert.Equal(t, received, requests.Load())
+
+	assert.Eventually(t, func() bool {
+		names, err := list.SpeciesNames(t.Context())
+		return err == nil && assert.ObjectsAreEqual(allSpecies, names)
+	}, time.Second, 20*time.Millisecond)
+}
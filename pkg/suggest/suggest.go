@@ -0,0 +1,74 @@
+package suggest
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fra98/pokedex/pkg/search"
+)
+
+// Source provides the names of all the species to suggest.
+type Source interface {
+	// SpeciesNames returns the names of all the species, or an error if they are not available (yet).
+	SpeciesNames(ctx context.Context) ([]string, error)
+}
+
+// Config contains the configuration of the suggester.
+type Config struct {
+	// MaxSuggestions is the maximum number of suggestions for a name.
+	MaxSuggestions int
+}
+
+// Suggester suggests the species names closest to a misspelled one, ranked by edit distance.
+type Suggester struct {
+	source Source
+	config Config
+}
+
+// NewSuggester returns a new Suggester suggesting the species names provided by the given source.
+func NewSuggester(source Source, cfg *Config) *Suggester {
+	config := *cfg
+	if config.MaxSuggestions <= 0 {
+		config.MaxSuggestions = 5
+	}
+	return &Suggester{source: source, config: config}
+}
+
+// Suggest returns up to the maximum number of species names close to the given one, the closest first.
+// A name is close if its edit distance is at most a third of the length of the given name, with at least 2 edits tolerated.
+// It returns no suggestions if the species names are not available.
+func (s *Suggester) Suggest(ctx context.Context, name string) []string {
+	names, err := s.source.SpeciesNames(ctx)
+	if err != nil {
+		// The sources log their failures, which would be repeated on every suggestion otherwise
+		slog.DebugContext(ctx, "Unable to suggest species names", "error", err)
+		return nil
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	maxDistance := max(utf8.RuneCountInString(name)/3, 2)
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, n := range names {
+		if d := search.Distance(name, n); d <= maxDistance && n != name {
+			candidates = append(candidates, candidate{name: n, distance: d})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.name, b.name))
+	})
+
+	suggestions := make([]string, 0, min(len(candidates), s.config.MaxSuggestions))
+	for _, c := range candidates[:min(len(candidates), s.config.MaxSuggestions)] {
+		suggestions = append(suggestions, c.name)
+	}
+	return suggestions
+}
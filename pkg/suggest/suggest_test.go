@@ -0,0 +1,58 @@
+package suggest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/suggest"
+)
+
+// staticSource is a suggest.Source providing the given species names, or failing if nil.
+type staticSource []string
+
+func (s staticSource) SpeciesNames(_ context.Context) ([]string, error) {
+	if s == nil {
+		return nil, fmt.Errorf("no species names: %w", errors.ErrNotReady)
+	}
+	return s, nil
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	source := staticSource{"pikachu", "raichu", "pichu", "mew", "mewtwo", "mr-mime"}
+	suggester := suggest.NewSuggester(source, &suggest.Config{MaxSuggestions: 2})
+
+	testCases := map[string]struct {
+		name     string
+		expected []string
+	}{
+		"typo":          {name: "pikachuu", expected: []string{"pikachu"}},
+		"transposition": {name: "mewtow", expected: []string{"mewtwo"}},
+		"ranked":        {name: "mewto", expected: []string{"mewtwo", "mew"}},
+		"limited":       {name: "pachu", expected: []string{"pichu", "pikachu"}},
+		"case":          {name: " Mr-Mim ", expected: []string{"mr-mime"}},
+		"exact":         {name: "pichu", expected: []string{"pikachu", "raichu"}},
+		"unknown":       {name: "missingno", expected: []string{}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, suggester.Suggest(context.Background(), tc.name))
+		})
+	}
+}
+
+func TestSuggest_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	// No suggestions are given while the species names are not available
+	suggester := suggest.NewSuggester(staticSource(nil), &suggest.Config{})
+	assert.Nil(t, suggester.Suggest(context.Background(), "pikachuu"))
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/fra98/pokedex/pkg/api"
+	"github.com/fra98/pokedex/pkg/cache"
 	"github.com/fra98/pokedex/pkg/client/pokeapi"
 	"github.com/fra98/pokedex/pkg/client/translator"
 	"github.com/fra98/pokedex/pkg/consts"
@@ -172,16 +173,16 @@ func TestPokemonAPIHandler(t *testing.T) { //nolint:funlen // skip long func len
 				defer translatorServer.Close()
 
 				// Create clients pointing to test servers
-				var pokeClient pokeapi.Client = pokeapi.NewPokeAPIClient(&pokeServer.URL)
-				var translatorClient translator.Client = translator.NewFunTranslationClient(&translatorServer.URL)
+				var pokeClient pokeapi.Client = pokeapi.NewPokeAPIClient(&pokeServer.URL, nil)
+				var translatorClient translator.Client = translator.NewFunTranslationClient(&translatorServer.URL, nil)
 				if cacheEnabled {
-					pokeClient = pokeapi.NewCachedPokeAPIClient(pokeClient, 1*time.Hour, 24*time.Hour)
-					translatorClient = translator.NewCachedTranslationClient(translatorClient, 1*time.Hour, 24*time.Hour)
+					pokeClient = pokeapi.NewCachedPokeAPIClient(pokeClient, cache.NewMemoryCache(1*time.Hour, 24*time.Hour), 1*time.Hour, 0)
+					translatorClient = translator.NewCachedTranslationClient(translatorClient, cache.NewMemoryCache(1*time.Hour, 24*time.Hour))
 				}
 
 				// Create service and handler
 				pokemonService := service.NewPokemonService(pokeClient, translatorClient)
-				pokemonHandler := api.NewPokemonHandler(pokemonService)
+				pokemonHandler := api.NewPokemonHandler(pokemonService, nil)
 
 				// Set up router
 				router := gin.New()
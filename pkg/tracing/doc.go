@@ -0,0 +1,2 @@
+// Package tracing provides the OpenTelemetry tracing setup of the application, along with helpers to instrument it.
+package tracing
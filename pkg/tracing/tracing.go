@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/fra98/pokedex/pkg/errors"
+)
+
+// Exporters of the spans.
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = "none"
+	// ExporterOTLP exports the spans to an OpenTelemetry collector, with the OTLP/HTTP protocol.
+	ExporterOTLP = "otlp"
+	// ExporterStdout prints the spans to the standard output, for local debugging.
+	ExporterStdout = "stdout"
+)
+
+// ServiceName is the name of the service reported in the spans.
+const ServiceName = "pokedex"
+
+const instrumentationName = "github.com/fra98/pokedex"
+
+// Config contains the configuration of the tracing.
+type Config struct {
+	// Exporter is the exporter of the spans (none, otlp, stdout).
+	Exporter string
+	// OTLPEndpoint is the host:port of the OTLP/HTTP collector (empty means the OTEL_EXPORTER_OTLP_* environment variables).
+	OTLPEndpoint string
+	// OTLPInsecure disables TLS towards the collector.
+	OTLPInsecure bool
+	// SampleRatio is the fraction of the traces started by the service which are sampled.
+	SampleRatio float64
+}
+
+// Setup configures the global tracer provider and the W3C trace context propagator according to the configuration.
+// It returns the function flushing the pending spans and stopping the exporter, to be called before exiting.
+func Setup(ctx context.Context, cfg *Config) (shutdown func(context.Context) error, err error) {
+	// Propagate the trace context, both inbound and outbound, even when not exporting the spans
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q: %w", cfg.Exporter, errors.ErrInvalidConfiguration)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span with the given name, child of the span in the context, if any.
+// It uses the global tracer provider, hence spans are not recorded until tracing is set up.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End ends the span, recording the error, if any. Not found resources are not considered errors,
+// since they are expected outcomes of the requests.
+func End(span trace.Span, err error) {
+	if err != nil && !stderrors.Is(err, errors.ErrResourceNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,59 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/fra98/pokedex/pkg/client/transport"
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/tracing"
+)
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	t.Parallel()
+
+	_, err := tracing.Setup(t.Context(), &tracing.Config{Exporter: "jaeger"})
+	require.ErrorIs(t, err, errors.ErrInvalidConfiguration)
+}
+
+//nolint:paralleltest // the test sets the global tracer provider and propagator
+func TestPropagation(t *testing.T) {
+	shutdown, err := tracing.Setup(t.Context(), &tracing.Config{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, shutdown(t.Context())) }()
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	var traceparent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	ctx, span := tracing.Start(t.Context(), "parent")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, http.NoBody)
+	require.NoError(t, err)
+	resp, err := transport.NewClient(&transport.Config{}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	tracing.End(span, nil)
+
+	// The upstream receives the trace context of the request
+	traceID := span.SpanContext().TraceID().String()
+	assert.Contains(t, traceparent, traceID)
+
+	// The upstream request is traced as child span
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, traceID, spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
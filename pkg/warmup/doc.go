@@ -0,0 +1,2 @@
+// Package warmup provides the cache warm-up, prefetching all the Pokemon species into the cached client.
+package warmup
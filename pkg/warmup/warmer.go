@@ -0,0 +1,208 @@
+package warmup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/health"
+)
+
+var (
+	_ health.Checker  = &Warmer{} // check if it implements the Checker interface.
+	_ health.Detailer = &Warmer{} // check if it implements the Detailer interface.
+)
+
+// Config contains the configuration of the cache warm-up.
+type Config struct {
+	// Concurrency is the maximum number of concurrent requests to the PokeAPI.
+	Concurrency int
+	// Interval is the minimum interval between two consecutive requests to the PokeAPI.
+	Interval time.Duration
+	// PageSize is the number of species retrieved per page of the species list.
+	PageSize int
+	// ReadyThreshold is the fraction of species to process before reporting ready, between 0 and 1.
+	ReadyThreshold float64
+	// RetryBackoffBase is the delay before retrying an aborted warm-up, doubled at each subsequent attempt.
+	RetryBackoffBase time.Duration
+	// RetryBackoffCap is the maximum delay between two attempts of the warm-up.
+	RetryBackoffCap time.Duration
+}
+
+// Warmer prefetches all the Pokemon species listed by the PokeAPI into the given client.
+type Warmer struct {
+	client pokeapi.Client
+	config Config
+
+	total  atomic.Int64
+	warmed atomic.Int64
+	failed atomic.Int64
+	// ready is set once the ready threshold is reached, while finished and aborted are set when Run returns.
+	ready    atomic.Bool
+	finished atomic.Bool
+	aborted  atomic.Bool
+}
+
+// NewWarmer returns a new Warmer prefetching the species into the given client, usually a cached one.
+func NewWarmer(client pokeapi.Client, cfg *Config) *Warmer {
+	config := *cfg
+	config.Concurrency = max(config.Concurrency, 1)
+	if config.PageSize <= 0 {
+		config.PageSize = 200
+	}
+	if config.RetryBackoffBase <= 0 {
+		config.RetryBackoffBase = 5 * time.Second
+	}
+	if config.RetryBackoffCap <= 0 {
+		config.RetryBackoffCap = 5 * time.Minute
+	}
+	return &Warmer{client: client, config: config}
+}
+
+// Run pages through the species list and prefetches every species, with bounded concurrency and rate pacing.
+// It returns when all the species are processed, or when the species list can not be retrieved or the context is canceled,
+// in which case the warm-up is aborted and not reported ready unless the ready threshold was already reached.
+// The progress is reset at each run.
+// Failures to retrieve a single species are logged and do not stop the warm-up.
+func (w *Warmer) Run(ctx context.Context) error {
+	start := time.Now()
+	w.total.Store(0)
+	w.warmed.Store(0)
+	w.failed.Store(0)
+	w.finished.Store(false)
+	w.aborted.Store(false)
+	names := make(chan string)
+
+	var wg sync.WaitGroup
+	for range w.config.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				w.prefetch(ctx, name)
+			}
+		}()
+	}
+
+	err := w.produce(ctx, names)
+	close(names)
+	wg.Wait()
+
+	duration := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		w.aborted.Store(true)
+		w.finished.Store(true)
+		slog.WarnContext(ctx, "Cache warm-up aborted",
+			"duration", duration, "warmed", w.warmed.Load(), "failed", w.failed.Load(), "error", err)
+		return err
+	}
+	w.ready.Store(true)
+	w.finished.Store(true)
+	slog.InfoContext(ctx, "Cache warm-up completed", "duration", duration, "warmed", w.warmed.Load(), "failed", w.failed.Load())
+	return nil
+}
+
+// RunWithRetries runs the warm-up, retrying it with exponential backoff while it is aborted before reaching the ready threshold,
+// so that a temporary failure to retrieve the species list does not keep the readiness failing.
+// It returns once the warm-up completed or reached the ready threshold, or when the context is canceled.
+func (w *Warmer) RunWithRetries(ctx context.Context) {
+	delay := w.config.RetryBackoffBase
+	for w.Run(ctx) != nil && !w.ready.Load() {
+		slog.InfoContext(ctx, "Retrying the cache warm-up", "delay", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay = min(2*delay, w.config.RetryBackoffCap)
+	}
+}
+
+// Progress returns the number of processed species (either warmed or failed) and the total number of species.
+func (w *Warmer) Progress() (processed, total int) {
+	return int(w.warmed.Load() + w.failed.Load()), int(w.total.Load())
+}
+
+// Details returns the progress of the warm-up, reported by the readiness checks.
+func (w *Warmer) Details() map[string]any {
+	processed, total := w.Progress()
+	return map[string]any{
+		"processed": processed,
+		"total":     total,
+		"finished":  w.finished.Load(),
+		"aborted":   w.aborted.Load(),
+	}
+}
+
+// Check returns nil if the warm-up completed or reached the ready threshold, an error otherwise,
+// including after the warm-up was aborted before reaching it.
+func (w *Warmer) Check(_ context.Context) error {
+	if w.ready.Load() {
+		return nil
+	}
+	processed, total := w.Progress()
+	if w.aborted.Load() {
+		return fmt.Errorf("cache warm-up aborted (%d/%d species): %w", processed, total, errors.ErrNotReady)
+	}
+	return fmt.Errorf("cache warm-up in progress (%d/%d species): %w", processed, total, errors.ErrNotReady)
+}
+
+// produce pages through the species list, sending the species names to the channel paced by the configured interval.
+func (w *Warmer) produce(ctx context.Context, names chan<- string) error {
+	var ticker *time.Ticker
+	if w.config.Interval > 0 {
+		ticker = time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+	}
+
+	for offset := 0; ; {
+		page, err := w.client.ListPokemonSpecies(ctx, offset, w.config.PageSize)
+		if err != nil {
+			return fmt.Errorf("failed to list species at offset %d: %w", offset, err)
+		}
+		w.total.Store(int64(page.Count))
+
+		for i := range page.Results {
+			if ticker != nil {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return fmt.Errorf("cache warm-up interrupted: %w", ctx.Err())
+				}
+			}
+			select {
+			case names <- page.Results[i].Name:
+			case <-ctx.Done():
+				return fmt.Errorf("cache warm-up interrupted: %w", ctx.Err())
+			}
+		}
+
+		offset += len(page.Results)
+		if page.Next == nil || len(page.Results) == 0 {
+			return nil
+		}
+	}
+}
+
+// prefetch retrieves a single species through the client, logging the progress every 10% of the species.
+func (w *Warmer) prefetch(ctx context.Context, name string) {
+	if _, err := w.client.GetPokemonSpecies(ctx, name); err != nil {
+		w.failed.Add(1)
+		slog.WarnContext(ctx, "Cache warm-up failed for species", "species", name, "error", err)
+	} else {
+		w.warmed.Add(1)
+	}
+
+	processed, total := w.Progress()
+	if total > 0 && float64(processed) >= w.config.ReadyThreshold*float64(total) {
+		w.ready.Store(true)
+	}
+	if step := max(total/10, 1); processed%step == 0 {
+		slog.InfoContext(ctx, "Cache warm-up progress", "processed", processed, "total", total)
+	}
+}
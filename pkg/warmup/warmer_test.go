@@ -0,0 +1,140 @@
+package warmup_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fra98/pokedex/pkg/cache"
+	"github.com/fra98/pokedex/pkg/client/pokeapi"
+	"github.com/fra98/pokedex/pkg/errors"
+	"github.com/fra98/pokedex/pkg/warmup"
+)
+
+// newPokeServer is a helper function to setup a test server for the PokeAPI serving the given number of species.
+// It returns the test server and a counter of the requests for single species.
+func newPokeServer(t *testing.T, count int) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var speciesRequests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		name := strings.TrimPrefix(r.URL.Path, "/pokemon-species/")
+		if name != "" {
+			// Single species request
+			speciesRequests.Add(1)
+			_, err := fmt.Fprintf(w, `{"name": %q}`, name)
+			assert.NoError(t, err)
+			return
+		}
+
+		// Species list request
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		list := pokeapi.NamedAPIResourceList{Count: count}
+		for i := offset; i < min(offset+limit, count); i++ {
+			list.Results = append(list.Results, pokeapi.NamedAPIResource{Name: "species-" + strconv.Itoa(i)})
+		}
+		if offset+limit < count {
+			next := "next"
+			list.Next = &next
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(list))
+	}))
+	return server, &speciesRequests
+}
+
+func TestWarmer_Run(t *testing.T) {
+	t.Parallel()
+
+	pokeServer, speciesRequests := newPokeServer(t, 25)
+	defer pokeServer.Close()
+
+	speciesCache := cache.NewMemoryCache(time.Hour, time.Hour)
+	client := pokeapi.NewCachedPokeAPIClient(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), speciesCache, time.Hour, 0)
+
+	warmer := warmup.NewWarmer(client, &warmup.Config{Concurrency: 3, Interval: time.Millisecond, PageSize: 10, ReadyThreshold: 1})
+	require.Error(t, warmer.Check(t.Context()), "should not be ready before the warm-up")
+
+	require.NoError(t, warmer.Run(t.Context()))
+
+	// All the species are prefetched into the cache, and ready is reported
+	processed, total := warmer.Progress()
+	assert.Equal(t, 25, processed)
+	assert.Equal(t, 25, total)
+	assert.Equal(t, int64(25), speciesRequests.Load())
+	assert.Len(t, speciesCache.Keys(pokeapi.SpeciesCacheKeyPrefix), 25)
+	require.NoError(t, warmer.Check(t.Context()))
+
+	// Subsequent requests are served by the cache
+	_, err := client.GetPokemonSpecies(t.Context(), "species-0")
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), speciesRequests.Load())
+}
+
+func TestWarmer_ListFailure(t *testing.T) {
+	t.Parallel()
+
+	pokeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer pokeServer.Close()
+
+	warmer := warmup.NewWarmer(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), &warmup.Config{ReadyThreshold: 1})
+
+	// The warm-up is aborted, and not reported ready with a cold cache
+	require.Error(t, warmer.Run(t.Context()))
+	require.ErrorIs(t, warmer.Check(t.Context()), errors.ErrNotReady)
+	assert.Equal(t, map[string]any{"processed": 0, "total": 0, "finished": true, "aborted": true}, warmer.Details())
+}
+
+func TestWarmer_Canceled(t *testing.T) {
+	t.Parallel()
+
+	pokeServer, _ := newPokeServer(t, 25)
+	defer pokeServer.Close()
+
+	// The warm-up is canceled before the ready threshold is reached
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	warmer := warmup.NewWarmer(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil), &warmup.Config{ReadyThreshold: 0.5})
+	require.Error(t, warmer.Run(ctx))
+	require.ErrorIs(t, warmer.Check(t.Context()), errors.ErrNotReady)
+}
+
+func TestWarmer_RunWithRetries(t *testing.T) {
+	t.Parallel()
+
+	healthyServer, _ := newPokeServer(t, 25)
+	defer healthyServer.Close()
+
+	// The species list can not be retrieved at the first two attempts
+	var listRequests atomic.Int64
+	pokeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pokemon-species/" && listRequests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		healthyServer.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer pokeServer.Close()
+
+	warmer := warmup.NewWarmer(pokeapi.NewPokeAPIClient(&pokeServer.URL, nil),
+		&warmup.Config{ReadyThreshold: 1, RetryBackoffBase: time.Millisecond, RetryBackoffCap: 2 * time.Millisecond})
+
+	// The aborted warm-up is retried until completed, and ready is reported
+	warmer.RunWithRetries(t.Context())
+	require.NoError(t, warmer.Check(t.Context()))
+	assert.Equal(t, map[string]any{"processed": 25, "total": 25, "finished": true, "aborted": false}, warmer.Details())
+}